@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys authenticates requests by the key in X-API-Key header,
+// keys are stored hashed.
+type APIKeys map[[sha256.Size]byte]Principal
+
+// ParseAPIKeys parses "name:key:role[:tenant[:worker_id]]" entries,
+// name is used as a subject of the principal.
+func ParseAPIKeys(entries []string) (APIKeys, error) {
+	keys := APIKeys{}
+	for i, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) < 3 || len(parts) > 5 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("api key %d: invalid format", i)
+		}
+		p := Principal{Subject: parts[0], Role: Role(parts[2])}
+		if len(parts) > 3 {
+			p.Tenant = parts[3]
+		}
+		if len(parts) > 4 {
+			workerID, err := uuid.Parse(parts[4])
+			if err != nil {
+				return nil, fmt.Errorf("api key %s: worker_id: %w", p.Subject, err)
+			}
+			p.WorkerID = workerID
+		}
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("api key %s: %w", p.Subject, err)
+		}
+		hash := sha256.Sum256([]byte(parts[1]))
+		if _, ok := keys[hash]; ok {
+			return nil, fmt.Errorf("api key %s: duplicate key", p.Subject)
+		}
+		keys[hash] = p
+	}
+	return keys, nil
+}
+
+func (k APIKeys) Authenticate(r *http.Request) (Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+	p, ok := k[sha256.Sum256([]byte(key))]
+	if !ok {
+		return Principal{}, fmt.Errorf("unknown api key: %w", ErrInvalidCredentials)
+	}
+	return p, nil
+}
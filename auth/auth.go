@@ -0,0 +1,79 @@
+// Package auth identifies callers of the API by their credentials.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RolePlanner Role = "planner"
+	// RoleWorker can only read own worker record and shifts.
+	RoleWorker Role = "worker"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RolePlanner, RoleWorker:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScopeFeed limits caller to reading calendar feeds.
+const ScopeFeed = "feed"
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject string
+	Role    Role
+	// Tenant restricts caller to the tenant, it's required for all roles
+	// but admin, admins without it can choose tenant with the header.
+	Tenant string
+	// WorkerID links caller with worker role to the worker.
+	WorkerID uuid.UUID
+	// Scope limits what caller can do with its role, empty scope is not limited.
+	Scope string
+}
+
+func (p Principal) validate() error {
+	if !p.Role.Valid() {
+		return fmt.Errorf("role %q: %w", p.Role, ErrInvalidCredentials)
+	}
+	if p.Scope != "" && p.Scope != ScopeFeed {
+		return fmt.Errorf("scope %q: %w", p.Scope, ErrInvalidCredentials)
+	}
+	if p.Role != RoleAdmin && p.Tenant == "" {
+		return fmt.Errorf("%s role without tenant: %w", p.Role, ErrInvalidCredentials)
+	}
+	if p.Role == RoleWorker && p.WorkerID == uuid.Nil {
+		return fmt.Errorf("worker role without worker_id: %w", ErrInvalidCredentials)
+	}
+	return nil
+}
+
+// Authenticator checks credentials of the request, ErrNoCredentials
+// is returned if request has no credentials of its kind.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Anonymous lets every caller in as an admin, it's meant only
+// for explicitly disabled authentication.
+type Anonymous struct{}
+
+func (Anonymous) Authenticate(*http.Request) (Principal, error) {
+	return Principal{Subject: "anonymous", Role: RoleAdmin}, nil
+}
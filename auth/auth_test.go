@@ -0,0 +1,145 @@
+package auth_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sp4rd4/wrkpln/auth"
+)
+
+func TestParseAPIKeys(t *testing.T) {
+	t.Parallel()
+	workerID := uuid.New()
+	tests := []struct {
+		name    string
+		entries []string
+		hasErr  bool
+	}{
+		{name: "Admin", entries: []string{"ops:secret:admin"}},
+		{name: "Tenant planner", entries: []string{"acme:secret:planner:acme"}},
+		{name: "Worker", entries: []string{"ann:secret:worker:acme:" + workerID.String()}},
+		{name: "Worker without id", entries: []string{"ann:secret:worker:acme"}, hasErr: true},
+		{name: "Planner without tenant", entries: []string{"acme:secret:planner"}, hasErr: true},
+		{name: "Worker without tenant", entries: []string{"ann:secret:worker::" + workerID.String()}, hasErr: true},
+		{name: "Unknown role", entries: []string{"ops:secret:root"}, hasErr: true},
+		{name: "Missing key", entries: []string{"ops::admin"}, hasErr: true},
+		{name: "Invalid worker id", entries: []string{"ann:secret:worker:acme:1"}, hasErr: true},
+		{name: "Duplicate key", entries: []string{"ops:secret:admin", "acme:secret:planner:acme"}, hasErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := auth.ParseAPIKeys(tt.entries)
+			if tt.hasErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAPIKeysAuthenticate(t *testing.T) {
+	t.Parallel()
+	keys, err := auth.ParseAPIKeys([]string{"acme:secret:planner:acme"})
+	assert.NoError(t, err)
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	_, err = keys.Authenticate(r)
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+
+	r.Header.Set(auth.APIKeyHeader, "wrong")
+	_, err = keys.Authenticate(r)
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	r.Header.Set(auth.APIKeyHeader, "secret")
+	p, err := keys.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "acme", Role: auth.RolePlanner, Tenant: "acme"}, p)
+}
+
+func TestAnonymousAuthenticate(t *testing.T) {
+	t.Parallel()
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	p, err := auth.Anonymous{}.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "anonymous", Role: auth.RoleAdmin}, p)
+}
+
+func TestJWT(t *testing.T) {
+	t.Parallel()
+	jwt := auth.NewJWT([]byte("key"))
+	principal := auth.Principal{Subject: "ann", Role: auth.RoleWorker, Tenant: "acme", WorkerID: uuid.New()}
+	valid, err := jwt.Issue(principal, time.Hour)
+	assert.NoError(t, err)
+	expired, err := jwt.Issue(principal, -time.Minute)
+	assert.NoError(t, err)
+	otherKey, err := auth.NewJWT([]byte("other")).Issue(principal, time.Hour)
+	assert.NoError(t, err)
+	feedPrincipal := principal
+	feedPrincipal.Scope = auth.ScopeFeed
+	feed, err := jwt.Issue(feedPrincipal, time.Hour)
+	assert.NoError(t, err)
+	otherScope := principal
+	otherScope.Scope = "write"
+	unknownScope, err := jwt.Issue(otherScope, time.Hour)
+	assert.NoError(t, err)
+	noTenant, err := jwt.Issue(auth.Principal{Subject: "jane", Role: auth.RolePlanner}, time.Hour)
+	assert.NoError(t, err)
+	parts := strings.Split(valid, ".")
+	// token with "none" algorithm and no signature
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	// token claims changed after signing
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"ann","role":"admin","exp":9999999999}`)) + "." + parts[2]
+	// correctly signed token without expiration
+	unsigned := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"ops","role":"admin"}`))
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte(unsigned))
+	noExp := unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name   string
+		header string
+		want   auth.Principal
+		expErr error
+	}{
+		{name: "Valid", header: "Bearer " + valid, want: principal},
+		{name: "No header", expErr: auth.ErrNoCredentials},
+		{name: "Other scheme", header: "Basic YWxhZGRpbjpvcGVuc2VzYW1l", expErr: auth.ErrNoCredentials},
+		{name: "Expired", header: "Bearer " + expired, expErr: auth.ErrInvalidCredentials},
+		{name: "Other key", header: "Bearer " + otherKey, expErr: auth.ErrInvalidCredentials},
+		{name: "None algorithm", header: "Bearer " + none, expErr: auth.ErrInvalidCredentials},
+		{name: "Tampered claims", header: "Bearer " + tampered, expErr: auth.ErrInvalidCredentials},
+		{name: "No expiration", header: "Bearer " + noExp, expErr: auth.ErrInvalidCredentials},
+		{name: "Feed scope", header: "Bearer " + feed, want: feedPrincipal},
+		{name: "Unknown scope", header: "Bearer " + unknownScope, expErr: auth.ErrInvalidCredentials},
+		{name: "Planner without tenant", header: "Bearer " + noTenant, expErr: auth.ErrInvalidCredentials},
+		{name: "Malformed", header: "Bearer abc", expErr: auth.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			p, err := jwt.Authenticate(r)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JWT authenticates requests by HS256 signed bearer token,
+// token must expire and is validated against the local key.
+type JWT struct {
+	key []byte
+}
+
+type claims struct {
+	Subject   string    `json:"sub"`
+	Role      Role      `json:"role"`
+	Tenant    string    `json:"tenant,omitempty"`
+	WorkerID  uuid.UUID `json:"worker_id,omitempty"`
+	Scope     string    `json:"scope,omitempty"`
+	ExpiresAt int64     `json:"exp"`
+	NotBefore int64     `json:"nbf,omitempty"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+func NewJWT(key []byte) JWT {
+	return JWT{key: key}
+}
+
+func (j JWT) Authenticate(r *http.Request) (Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return Principal{}, ErrNoCredentials
+	}
+	return j.Parse(token)
+}
+
+// Parse validates token signature and expiration.
+func (j JWT) Parse(token string) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, fmt.Errorf("token format: %w", ErrInvalidCredentials)
+	}
+	h := header{}
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return Principal{}, fmt.Errorf("token header: %w", err)
+	}
+	// algorithm from the header is never trusted, only HS256 is accepted
+	if h.Alg != "HS256" {
+		return Principal{}, fmt.Errorf("token algorithm %q: %w", h.Alg, ErrInvalidCredentials)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, j.sign(parts[0]+"."+parts[1])) {
+		return Principal{}, fmt.Errorf("token signature: %w", ErrInvalidCredentials)
+	}
+	c := claims{}
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Principal{}, fmt.Errorf("token claims: %w", err)
+	}
+	now := time.Now().Unix()
+	if c.ExpiresAt == 0 || now >= c.ExpiresAt {
+		return Principal{}, fmt.Errorf("token expired: %w", ErrInvalidCredentials)
+	}
+	if now < c.NotBefore {
+		return Principal{}, fmt.Errorf("token not valid yet: %w", ErrInvalidCredentials)
+	}
+	p := Principal{Subject: c.Subject, Role: c.Role, Tenant: c.Tenant, WorkerID: c.WorkerID, Scope: c.Scope}
+	if err := p.validate(); err != nil {
+		return Principal{}, err
+	}
+	return p, nil
+}
+
+// Issue creates token of the principal valid for ttl.
+func (j JWT) Issue(p Principal, ttl time.Duration) (string, error) {
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("marshal header: %w", err)
+	}
+	c, err := json.Marshal(claims{
+		Subject: p.Subject, Role: p.Role, Tenant: p.Tenant, WorkerID: p.WorkerID, Scope: p.Scope,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal claims: %w", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(j.sign(unsigned)), nil
+}
+
+func (j JWT) sign(data string) []byte {
+	mac := hmac.New(sha256.New, j.key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("decode: %w", ErrInvalidCredentials)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unmarshal: %w", ErrInvalidCredentials)
+	}
+	return nil
+}
@@ -11,4 +11,30 @@ type Config struct {
 	MaxHeaderBytes  int           `env:"HTTP_MAX_HEADER_BYTES" envDefault:"1048576"` //1MB
 	DBPath          string        `env:"SQLITE_DB" envDefault:"work_planning.db"`
 	DBSchemaPath    string        `env:"SQLITE_SCHEMA" envDefault:"db/schema.sql"`
+	DBMigrationsDir string        `env:"SQLITE_MIGRATIONS" envDefault:"db/migrations"`
+	ConflictPolicy  string        `env:"CONFLICT_POLICY" envDefault:"one_per_day"` // one_per_day, no_overlap, min_rest
+	MinRest         time.Duration `env:"MIN_REST" envDefault:"11h"`
+	// ComplianceRules are "name:limit[:mode]" entries, e.g. max_weekly_hours:48h,
+	// max_consecutive_days:6, min_rest:11h, min_days_off:2/7, mode is block (default) or warn.
+	// min_rest rule can't be combined with min_rest conflict policy.
+	ComplianceRules []string `env:"COMPLIANCE_RULES"`
+	// Hours above overtime thresholds are reported as overtime, zero disables the threshold.
+	OvertimeDaily  time.Duration `env:"OVERTIME_DAILY"`
+	OvertimeWeekly time.Duration `env:"OVERTIME_WEEKLY" envDefault:"40h"`
+	// StaffingTargets are "[days/]HH-HH:headcount" entries coverage is compared with,
+	// e.g. mon-fri/08-18:3, sat-sun/10-16:1.
+	StaffingTargets []string `env:"STAFFING_TARGETS"`
+	// DefaultTenant is used for requests without X-Tenant-ID header,
+	// empty value makes the header required.
+	DefaultTenant string `env:"DEFAULT_TENANT" envDefault:"default"`
+	// APIKeys are "name:key:role[:tenant[:worker_id]]" entries,
+	// roles are admin, planner and worker.
+	APIKeys []string `env:"API_KEYS"`
+	// JWTKey is a secret HS256 bearer tokens are signed with.
+	// Either API keys or JWT key must be set unless authentication is disabled.
+	JWTKey string `env:"JWT_KEY"`
+	// FeedTokenTTL is a lifetime of calendar feed tokens, they are issued only with JWT key.
+	FeedTokenTTL time.Duration `env:"FEED_TOKEN_TTL" envDefault:"8760h"`
+	// AuthDisabled lets every caller in as an admin, it's meant only for local development.
+	AuthDisabled bool `env:"AUTH_DISABLED"`
 }
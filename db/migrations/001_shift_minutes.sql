@@ -0,0 +1,5 @@
+ALTER TABLE shifts ADD COLUMN start_minute smallint NOT NULL DEFAULT 0;
+ALTER TABLE shifts ADD COLUMN end_minute smallint NOT NULL DEFAULT 0;
+UPDATE shifts SET start_minute = start_hour * 60, end_minute = end_hour * 60;
+ALTER TABLE shifts DROP COLUMN start_hour;
+ALTER TABLE shifts DROP COLUMN end_hour;
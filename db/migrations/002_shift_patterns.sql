@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS shift_patterns (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 kind text NOT NULL,
+	 weekdays tinyint NOT NULL,
+	 on_days smallint NOT NULL,
+	 off_days smallint NOT NULL,
+	 anchor date NOT NULL,
+	 start_minute smallint NOT NULL,
+	 end_minute smallint NOT NULL,
+	 FOREIGN KEY(worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS shift_patterns_worker_id_idx ON shift_patterns(worker_id);
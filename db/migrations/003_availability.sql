@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS availabilities (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 weekdays tinyint NOT NULL,
+	 start_minute smallint NOT NULL,
+	 end_minute smallint NOT NULL,
+	 FOREIGN KEY(worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS availabilities_worker_id_idx ON availabilities(worker_id);
+
+CREATE TABLE IF NOT EXISTS unavailabilities (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 starts_at datetime NOT NULL,
+	 ends_at datetime NOT NULL,
+	 reason text NOT NULL,
+	 FOREIGN KEY(worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS unavailabilities_worker_id_starts_at_idx ON unavailabilities(worker_id, starts_at);
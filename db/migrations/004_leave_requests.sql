@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS leave_requests (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 starts_at datetime NOT NULL,
+	 ends_at datetime NOT NULL,
+	 reason text NOT NULL,
+	 status text NOT NULL,
+	 FOREIGN KEY(worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS leave_requests_worker_id_starts_at_idx ON leave_requests(worker_id, starts_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS swap_requests (
+	 id uuid NOT NULL PRIMARY KEY,
+	 shift_id text NOT NULL,
+	 other_shift_id text,
+	 from_worker_id text NOT NULL,
+	 to_worker_id text NOT NULL,
+	 status text NOT NULL,
+	 FOREIGN KEY(from_worker_id) REFERENCES workers(id),
+	 FOREIGN KEY(to_worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS swap_requests_from_worker_id_idx ON swap_requests(from_worker_id);
+CREATE INDEX IF NOT EXISTS swap_requests_to_worker_id_idx ON swap_requests(to_worker_id);
@@ -0,0 +1,2 @@
+ALTER TABLE workers ADD COLUMN skills text;
+ALTER TABLE shifts ADD COLUMN skills text;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS locations (
+	 id uuid NOT NULL PRIMARY KEY,
+	 name text NOT NULL
+);
+ALTER TABLE workers ADD COLUMN location_id text REFERENCES locations(id);
+ALTER TABLE shifts ADD COLUMN location_id text REFERENCES locations(id);
+CREATE INDEX IF NOT EXISTS workers_location_id_idx ON workers(location_id);
+CREATE INDEX IF NOT EXISTS shifts_location_id_idx ON shifts(location_id);
@@ -0,0 +1,16 @@
+ALTER TABLE workers ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE shifts ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE shift_patterns ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE availabilities ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE unavailabilities ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE leave_requests ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE swap_requests ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE locations ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS workers_tenant_id_idx ON workers(tenant_id);
+CREATE INDEX IF NOT EXISTS shifts_tenant_id_idx ON shifts(tenant_id);
+CREATE INDEX IF NOT EXISTS shift_patterns_tenant_id_idx ON shift_patterns(tenant_id);
+CREATE INDEX IF NOT EXISTS availabilities_tenant_id_idx ON availabilities(tenant_id);
+CREATE INDEX IF NOT EXISTS unavailabilities_tenant_id_idx ON unavailabilities(tenant_id);
+CREATE INDEX IF NOT EXISTS leave_requests_tenant_id_idx ON leave_requests(tenant_id);
+CREATE INDEX IF NOT EXISTS swap_requests_tenant_id_idx ON swap_requests(tenant_id);
+CREATE INDEX IF NOT EXISTS locations_tenant_id_idx ON locations(tenant_id);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS audit_entries (
+	 id uuid NOT NULL PRIMARY KEY,
+	 recorded_at datetime NOT NULL,
+	 actor text NOT NULL,
+	 entity text NOT NULL,
+	 entity_id text NOT NULL,
+	 action text NOT NULL,
+	 before text,
+	 after text,
+	 tenant_id text NOT NULL DEFAULT 'default'
+);
+CREATE INDEX IF NOT EXISTS audit_entries_tenant_id_recorded_at_idx ON audit_entries(tenant_id, recorded_at, id);
+CREATE INDEX IF NOT EXISTS audit_entries_entity_id_idx ON audit_entries(entity_id);
+CREATE TRIGGER IF NOT EXISTS audit_entries_no_update BEFORE UPDATE ON audit_entries
+BEGIN
+	SELECT RAISE(ABORT, 'audit entries are append-only');
+END;
+CREATE TRIGGER IF NOT EXISTS audit_entries_no_delete BEFORE DELETE ON audit_entries
+BEGIN
+	SELECT RAISE(ABORT, 'audit entries are append-only');
+END;
@@ -0,0 +1,21 @@
+CREATE TABLE shifts_new (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text,
+	 date date NOT NULL,
+	 start_minute smallint NOT NULL,
+	 end_minute smallint NOT NULL,
+	 skills text,
+	 location_id text,
+	 tenant_id text NOT NULL DEFAULT 'default',
+	 FOREIGN KEY(worker_id) REFERENCES workers(id),
+	 FOREIGN KEY(location_id) REFERENCES locations(id)
+);
+INSERT INTO shifts_new (id, worker_id, date, start_minute, end_minute, skills, location_id, tenant_id)
+SELECT id, NULLIF(worker_id, '00000000-0000-0000-0000-000000000000'), date, start_minute, end_minute, skills, location_id, tenant_id
+FROM shifts;
+DROP TABLE shifts;
+ALTER TABLE shifts_new RENAME TO shifts;
+CREATE INDEX IF NOT EXISTS shifts_tenant_id_idx ON shifts(tenant_id);
+CREATE INDEX IF NOT EXISTS shifts_date_worker_id_idx ON shifts(date, worker_id);
+CREATE INDEX IF NOT EXISTS shifts_date_open_worker_id_idx ON shifts(date, COALESCE(worker_id, '00000000-0000-0000-0000-000000000000'), id);
+CREATE INDEX IF NOT EXISTS shifts_location_id_idx ON shifts(location_id);
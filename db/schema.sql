@@ -1,15 +1,125 @@
 CREATE TABLE IF NOT EXISTS workers (
 	 id uuid NOT NULL PRIMARY KEY,
-	 name text NOT NULL
+	 name text NOT NULL,
+	 skills text,
+	 location_id text,
+	 tenant_id text NOT NULL DEFAULT 'default',
+	 FOREIGN KEY(location_id) REFERENCES locations(id)
 );
+CREATE INDEX IF NOT EXISTS workers_tenant_id_idx ON workers(tenant_id);
 CREATE INDEX IF NOT EXISTS workers_name_idx ON workers(name COLLATE NOCASE);
+CREATE INDEX IF NOT EXISTS workers_location_id_idx ON workers(location_id);
 
 CREATE TABLE IF NOT EXISTS shifts (
 	 id uuid NOT NULL PRIMARY KEY,
-	 worker_id text NOT NULL,
+	 worker_id text,
 	 date date NOT NULL,
-	 start_hour tinyint NOT NULL,
-	 end_hour tinyint NOT NULL,
+	 start_minute smallint NOT NULL,
+	 end_minute smallint NOT NULL,
+	 skills text,
+	 location_id text,
+	 tenant_id text NOT NULL DEFAULT 'default',
+	 FOREIGN KEY(worker_id) REFERENCES workers(id),
+	 FOREIGN KEY(location_id) REFERENCES locations(id)
+);
+CREATE INDEX IF NOT EXISTS shifts_tenant_id_idx ON shifts(tenant_id);
+CREATE INDEX IF NOT EXISTS shifts_date_worker_id_idx ON shifts(date, worker_id);
+CREATE INDEX IF NOT EXISTS shifts_date_open_worker_id_idx ON shifts(date, COALESCE(worker_id, '00000000-0000-0000-0000-000000000000'), id);
+CREATE INDEX IF NOT EXISTS shifts_location_id_idx ON shifts(location_id);
+
+CREATE TABLE IF NOT EXISTS shift_patterns (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 kind text NOT NULL,
+	 weekdays tinyint NOT NULL,
+	 on_days smallint NOT NULL,
+	 off_days smallint NOT NULL,
+	 anchor date NOT NULL,
+	 start_minute smallint NOT NULL,
+	 end_minute smallint NOT NULL,
+	 tenant_id text NOT NULL DEFAULT 'default',
+	 FOREIGN KEY(worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS shift_patterns_tenant_id_idx ON shift_patterns(tenant_id);
+CREATE INDEX IF NOT EXISTS shift_patterns_worker_id_idx ON shift_patterns(worker_id);
+
+CREATE TABLE IF NOT EXISTS availabilities (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 weekdays tinyint NOT NULL,
+	 start_minute smallint NOT NULL,
+	 end_minute smallint NOT NULL,
+	 tenant_id text NOT NULL DEFAULT 'default',
+	 FOREIGN KEY(worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS availabilities_tenant_id_idx ON availabilities(tenant_id);
+CREATE INDEX IF NOT EXISTS availabilities_worker_id_idx ON availabilities(worker_id);
+
+CREATE TABLE IF NOT EXISTS unavailabilities (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 starts_at datetime NOT NULL,
+	 ends_at datetime NOT NULL,
+	 reason text NOT NULL,
+	 tenant_id text NOT NULL DEFAULT 'default',
 	 FOREIGN KEY(worker_id) REFERENCES workers(id)
 );
-CREATE INDEX IF NOT EXISTS shifts_date_worker_id_idx ON shifts(date, worker_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS unavailabilities_tenant_id_idx ON unavailabilities(tenant_id);
+CREATE INDEX IF NOT EXISTS unavailabilities_worker_id_starts_at_idx ON unavailabilities(worker_id, starts_at);
+
+CREATE TABLE IF NOT EXISTS leave_requests (
+	 id uuid NOT NULL PRIMARY KEY,
+	 worker_id text NOT NULL,
+	 starts_at datetime NOT NULL,
+	 ends_at datetime NOT NULL,
+	 reason text NOT NULL,
+	 status text NOT NULL,
+	 tenant_id text NOT NULL DEFAULT 'default',
+	 FOREIGN KEY(worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS leave_requests_tenant_id_idx ON leave_requests(tenant_id);
+CREATE INDEX IF NOT EXISTS leave_requests_worker_id_starts_at_idx ON leave_requests(worker_id, starts_at);
+
+CREATE TABLE IF NOT EXISTS swap_requests (
+	 id uuid NOT NULL PRIMARY KEY,
+	 shift_id text NOT NULL,
+	 other_shift_id text,
+	 from_worker_id text NOT NULL,
+	 to_worker_id text NOT NULL,
+	 status text NOT NULL,
+	 tenant_id text NOT NULL DEFAULT 'default',
+	 FOREIGN KEY(from_worker_id) REFERENCES workers(id),
+	 FOREIGN KEY(to_worker_id) REFERENCES workers(id)
+);
+CREATE INDEX IF NOT EXISTS swap_requests_tenant_id_idx ON swap_requests(tenant_id);
+CREATE INDEX IF NOT EXISTS swap_requests_from_worker_id_idx ON swap_requests(from_worker_id);
+CREATE INDEX IF NOT EXISTS swap_requests_to_worker_id_idx ON swap_requests(to_worker_id);
+
+CREATE TABLE IF NOT EXISTS locations (
+	 id uuid NOT NULL PRIMARY KEY,
+	 name text NOT NULL,
+	 tenant_id text NOT NULL DEFAULT 'default'
+);
+CREATE INDEX IF NOT EXISTS locations_tenant_id_idx ON locations(tenant_id);
+
+CREATE TABLE IF NOT EXISTS audit_entries (
+	 id uuid NOT NULL PRIMARY KEY,
+	 recorded_at datetime NOT NULL,
+	 actor text NOT NULL,
+	 entity text NOT NULL,
+	 entity_id text NOT NULL,
+	 action text NOT NULL,
+	 before text,
+	 after text,
+	 tenant_id text NOT NULL DEFAULT 'default'
+);
+CREATE INDEX IF NOT EXISTS audit_entries_tenant_id_recorded_at_idx ON audit_entries(tenant_id, recorded_at, id);
+CREATE INDEX IF NOT EXISTS audit_entries_entity_id_idx ON audit_entries(entity_id);
+CREATE TRIGGER IF NOT EXISTS audit_entries_no_update BEFORE UPDATE ON audit_entries
+BEGIN
+	SELECT RAISE(ABORT, 'audit entries are append-only');
+END;
+CREATE TRIGGER IF NOT EXISTS audit_entries_no_delete BEFORE DELETE ON audit_entries
+BEGIN
+	SELECT RAISE(ABORT, 'audit entries are append-only');
+END;
\ No newline at end of file
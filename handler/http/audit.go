@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+func (h PlanningHandler) AuditEntries(c *gin.Context) {
+	af, err := auditFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entries, next, err := h.plan.AuditEntries(c.Request.Context(), af)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("list audit entries error", "error", err)
+		return
+	}
+
+	setNextLink(c, next)
+	c.JSON(http.StatusOK, entries)
+}
+
+func auditFilter(query url.Values) (planner.AuditFilter, error) {
+	af := planner.AuditFilter{}
+	if entity := query.Get("entity"); entity != "" {
+		af.Entity = &entity
+	}
+	if entityIDStr := query.Get("entity_id"); entityIDStr != "" {
+		entityID, err := uuid.Parse(entityIDStr)
+		if err != nil {
+			return planner.AuditFilter{}, fmt.Errorf("entity_id: %w", err)
+		}
+		af.EntityID = &entityID
+	}
+	if actor := query.Get("actor"); actor != "" {
+		af.Actor = &actor
+	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return planner.AuditFilter{}, fmt.Errorf("from: %w", err)
+		}
+		af.From = &from
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return planner.AuditFilter{}, fmt.Errorf("to: %w", err)
+		}
+		af.To = &to
+	}
+	// entries are always in chronological order, so sort is ignored
+	var err error
+	_, af.Limit, af.After, err = pageParams(query)
+	if err != nil {
+		return planner.AuditFilter{}, err
+	}
+	return af, nil
+}
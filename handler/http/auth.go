@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/auth"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+const (
+	principalKey = "principal"
+	// feedTokenKey marks request with token taken from the query.
+	feedTokenKey = "feed_token"
+)
+
+// Authenticate identifies caller with the first authenticator which finds
+// its credentials in the request, callers without known credentials are rejected.
+// Subject of the caller is recorded as actor of changes made by the request.
+// Callers with feed scope can only read calendar feeds and token from the query
+// is accepted only with feed scope.
+func Authenticate(authenticators []auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, a := range authenticators {
+			p, err := a.Authenticate(c.Request)
+			if errors.Is(err, auth.ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				slog.Debug("authentication failed", "error", err)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "401 unauthorized"})
+				return
+			}
+			feed := c.Request.Method == http.MethodGet && isFeed(c.Request.URL.Path)
+			if (p.Scope == auth.ScopeFeed && !feed) || (c.GetBool(feedTokenKey) && p.Scope != auth.ScopeFeed) {
+				slog.Debug("token scope rejected", "scope", p.Scope, "path", c.Request.URL.Path)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "401 unauthorized"})
+				return
+			}
+			setPrincipal(c, p)
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "401 unauthorized"})
+	}
+}
+
+// FeedToken lets calendar apps, which can't set headers, pass feed token
+// of calendar feeds in the token query parameter. Token is removed from the
+// query, so it must run before request logging.
+func FeedToken(c *gin.Context) {
+	if !isFeed(c.Request.URL.Path) {
+		return
+	}
+	query := c.Request.URL.Query()
+	token := query.Get("token")
+	if token == "" {
+		return
+	}
+	if c.GetHeader("Authorization") == "" {
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+		c.Set(feedTokenKey, true)
+	}
+	query.Del("token")
+	c.Request.URL.RawQuery = query.Encode()
+}
+
+func isFeed(path string) bool {
+	return strings.HasSuffix(path, ".ics")
+}
+
+// Allow lets callers with one of the roles through.
+func Allow(roles ...auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, principal(c).Role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "403 forbidden"})
+		}
+	}
+}
+
+func setPrincipal(c *gin.Context, p auth.Principal) {
+	c.Set(principalKey, p)
+	c.Request = c.Request.WithContext(planner.WithActor(c.Request.Context(), p.Subject))
+}
+
+func principal(c *gin.Context) auth.Principal {
+	p, _ := c.Get(principalKey)
+	// zero principal has no role and is not allowed anywhere
+	principal, _ := p.(auth.Principal)
+	return principal
+}
+
+// ownerCheck aborts request of caller with worker role
+// if records of another worker are requested.
+func ownerCheck(c *gin.Context, workerID uuid.UUID) bool {
+	p := principal(c)
+	if p.Role == auth.RoleWorker && p.WorkerID != workerID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "403 forbidden"})
+		return false
+	}
+	return true
+}
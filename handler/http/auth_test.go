@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sp4rd4/wrkpln/auth"
+	handler "github.com/sp4rd4/wrkpln/handler/http"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+// static lets every caller in as the principal, skipping validation
+// of credentials, as a misconfigured authenticator would.
+type static auth.Principal
+
+func (s static) Authenticate(*http.Request) (auth.Principal, error) {
+	return auth.Principal(s), nil
+}
+
+func tenantEngine(authenticators ...auth.Authenticator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(handler.Authenticate(authenticators), handler.TenantCheck("default"))
+	engine.GET("/tenant", func(c *gin.Context) {
+		tenant, _ := planner.TenantFrom(c.Request.Context())
+		c.String(http.StatusOK, tenant)
+	})
+	return engine
+}
+
+func TestTenantCheck(t *testing.T) {
+	t.Parallel()
+	jwt := auth.NewJWT([]byte("key"))
+	tenantless, err := jwt.Issue(auth.Principal{Subject: "jane", Role: auth.RolePlanner}, time.Hour)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		auth      auth.Authenticator
+		bearer    string
+		tenant    string
+		expStatus int
+		expTenant string
+	}{
+		{
+			name: "Admin chooses tenant", auth: static{Subject: "ops", Role: auth.RoleAdmin},
+			tenant: "globex", expStatus: http.StatusOK, expTenant: "globex",
+		},
+		{
+			name: "Admin default tenant", auth: static{Subject: "ops", Role: auth.RoleAdmin},
+			expStatus: http.StatusOK, expTenant: "default",
+		},
+		{
+			name: "Bound planner", auth: static{Subject: "jane", Role: auth.RolePlanner, Tenant: "acme"},
+			expStatus: http.StatusOK, expTenant: "acme",
+		},
+		{
+			name: "Bound planner other tenant", auth: static{Subject: "jane", Role: auth.RolePlanner, Tenant: "acme"},
+			tenant: "globex", expStatus: http.StatusForbidden,
+		},
+		{
+			name: "Tenantless planner other tenant", auth: static{Subject: "jane", Role: auth.RolePlanner},
+			tenant: "globex", expStatus: http.StatusForbidden,
+		},
+		{
+			name: "Tenantless planner token", auth: jwt, bearer: tenantless,
+			tenant: "globex", expStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+			if tt.bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			if tt.tenant != "" {
+				r.Header.Set(handler.TenantHeader, tt.tenant)
+			}
+			w := httptest.NewRecorder()
+			tenantEngine(tt.auth).ServeHTTP(w, r)
+			assert.Equal(t, tt.expStatus, w.Code)
+			if tt.expStatus == http.StatusOK {
+				assert.Equal(t, tt.expTenant, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFeedToken(t *testing.T) {
+	t.Parallel()
+	jwt := auth.NewJWT([]byte("key"))
+	worker := auth.Principal{Subject: "ann", Role: auth.RoleWorker, Tenant: "acme", WorkerID: uuid.New()}
+	full, err := jwt.Issue(worker, time.Hour)
+	assert.NoError(t, err)
+	worker.Scope = auth.ScopeFeed
+	feed, err := jwt.Issue(worker, time.Hour)
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(handler.FeedToken, handler.Authenticate([]auth.Authenticator{jwt}))
+	ok := func(c *gin.Context) { c.String(http.StatusOK, c.Request.URL.RawQuery) }
+	engine.GET("/shifts.ics", ok)
+	engine.GET("/shifts", ok)
+	engine.POST("/shift/claim", ok)
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		bearer    string
+		expStatus int
+	}{
+		{name: "Feed token in query", method: http.MethodGet, target: "/shifts.ics?token=" + feed, expStatus: http.StatusOK},
+		{name: "Feed token in header", method: http.MethodGet, target: "/shifts.ics", bearer: feed, expStatus: http.StatusOK},
+		{name: "Full token in query", method: http.MethodGet, target: "/shifts.ics?token=" + full, expStatus: http.StatusUnauthorized},
+		{name: "Full token in header", method: http.MethodGet, target: "/shifts.ics", bearer: full, expStatus: http.StatusOK},
+		{name: "Feed token on list", method: http.MethodGet, target: "/shifts", bearer: feed, expStatus: http.StatusUnauthorized},
+		{name: "Feed token on claim", method: http.MethodPost, target: "/shift/claim", bearer: feed, expStatus: http.StatusUnauthorized},
+		{name: "Query token outside feeds", method: http.MethodGet, target: "/shifts?token=" + feed, expStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, r)
+			assert.Equal(t, tt.expStatus, w.Code)
+			// token is never passed further in the query
+			assert.NotContains(t, w.Body.String(), "token=")
+		})
+	}
+}
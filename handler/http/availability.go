@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+func (h PlanningHandler) CreateAvailability(c *gin.Context) {
+	workerID, ok := parseID(c)
+	if !ok {
+		return
+	}
+	availability := planner.Availability{}
+	if errorReturned := parseJson(c, &availability); !errorReturned {
+		return
+	}
+	availability.WorkerID = workerID
+
+	availability, err := h.plan.CreateAvailability(c.Request.Context(), availability)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("create availability error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, availability)
+}
+
+func (h PlanningHandler) Availabilities(c *gin.Context) {
+	workerID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	availabilities, err := h.plan.Availabilities(
+		c.Request.Context(), planner.AvailabilityFilter{WorkerID: &workerID},
+	)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("list availabilities error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availabilities)
+}
+
+func (h PlanningHandler) UpdateAvailability(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	availability := planner.Availability{}
+	if errorReturned := parseJson(c, &availability); !errorReturned {
+		return
+	}
+	availability.ID = id
+
+	availability, err := h.plan.UpdateAvailability(c.Request.Context(), availability)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("update availability error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func (h PlanningHandler) DeleteAvailability(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	err := h.plan.DeleteAvailability(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("delete availability error", "error", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h PlanningHandler) CreateUnavailability(c *gin.Context) {
+	workerID, ok := parseID(c)
+	if !ok {
+		return
+	}
+	unavailability := planner.Unavailability{}
+	if errorReturned := parseJson(c, &unavailability); !errorReturned {
+		return
+	}
+	unavailability.WorkerID = workerID
+
+	unavailability, err := h.plan.CreateUnavailability(c.Request.Context(), unavailability)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("create unavailability error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, unavailability)
+}
+
+func (h PlanningHandler) Unavailabilities(c *gin.Context) {
+	workerID, ok := parseID(c)
+	if !ok {
+		return
+	}
+	uf, err := unavailabilityFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uf.WorkerID = &workerID
+
+	unavailabilities, err := h.plan.Unavailabilities(c.Request.Context(), uf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("list unavailabilities error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, unavailabilities)
+}
+
+func unavailabilityFilter(query url.Values) (planner.UnavailabilityFilter, error) {
+	uf := planner.UnavailabilityFilter{}
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return planner.UnavailabilityFilter{}, fmt.Errorf("from: %w", err)
+		}
+		uf.From = &from
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return planner.UnavailabilityFilter{}, fmt.Errorf("to: %w", err)
+		}
+		uf.To = &to
+	}
+	if uf.From != nil && uf.To != nil && uf.To.Before(*uf.From) {
+		return planner.UnavailabilityFilter{}, errors.New("to: before from")
+	}
+	return uf, nil
+}
+
+func (h PlanningHandler) UpdateUnavailability(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	unavailability := planner.Unavailability{}
+	if errorReturned := parseJson(c, &unavailability); !errorReturned {
+		return
+	}
+	unavailability.ID = id
+
+	unavailability, err := h.plan.UpdateUnavailability(c.Request.Context(), unavailability)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("update unavailability error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, unavailability)
+}
+
+func (h PlanningHandler) DeleteUnavailability(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	err := h.plan.DeleteUnavailability(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("delete unavailability error", "error", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/auth"
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/calendar"
+)
+
+// Calendar feed range starts feedLookbackMonths before today if it isn't set,
+// so recent shifts stay in calendars, and lasts feedDays unless end is set.
+const (
+	feedLookbackMonths = 1
+	feedDays           = 365
+)
+
+// FeedToken issues token of the caller which only gives access to calendar feeds,
+// so it can be stored by calendar apps in the feed URL.
+func (h PlanningHandler) FeedToken(c *gin.Context) {
+	if h.feedTokens == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "feed tokens are not enabled"})
+		return
+	}
+	p := principal(c)
+	p.Scope = auth.ScopeFeed
+	token, err := h.feedTokens.jwt.Issue(p, h.feedTokens.ttl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("issue feed token error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":      token,
+		"expires_at": time.Now().UTC().Add(h.feedTokens.ttl).Truncate(time.Second),
+	})
+}
+
+// WorkerCalendar is a calendar feed of worker shifts.
+func (h PlanningHandler) WorkerCalendar(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if !ownerCheck(c, id) {
+		return
+	}
+	cf, err := calendarFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cf.WorkerID = &id
+	h.calendar(c, cf)
+}
+
+// Calendar is a calendar feed of all shifts, including open ones.
+func (h PlanningHandler) Calendar(c *gin.Context) {
+	cf, err := calendarFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.calendar(c, cf)
+}
+
+func (h PlanningHandler) calendar(c *gin.Context, cf calendar.Filter) {
+	cal, err := h.feeds.Calendar(c.Request.Context(), cf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("calendar error", "error", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="shifts.ics"`)
+	// calendar apps must get current shifts on every refresh
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	if _, err := cal.WriteTo(c.Writer); err != nil {
+		slog.Error("calendar write error", "error", err)
+	}
+}
+
+func calendarFilter(query url.Values) (calendar.Filter, error) {
+	cf := calendar.Filter{From: time.Now().UTC().AddDate(0, -feedLookbackMonths, 0)}
+	if locationIDStr := query.Get("location_id"); locationIDStr != "" {
+		locationID, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			return calendar.Filter{}, fmt.Errorf("location_id: %w", err)
+		}
+		cf.LocationID = &locationID
+	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return calendar.Filter{}, fmt.Errorf("from: %w", err)
+		}
+		cf.From = from
+	}
+	cf.To = cf.From.AddDate(0, 0, feedDays)
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return calendar.Filter{}, fmt.Errorf("to: %w", err)
+		}
+		cf.To = to
+	}
+	return cf, nil
+}
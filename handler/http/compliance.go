@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+// Compliance reports violations of compliance rules in the date range.
+func (h PlanningHandler) Compliance(c *gin.Context) {
+	cf, err := complianceFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	violations, err := h.plan.CheckCompliance(c.Request.Context(), cf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("check compliance error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, violations)
+}
+
+func complianceFilter(query url.Values) (planner.ComplianceFilter, error) {
+	cf := planner.ComplianceFilter{}
+	if workerIDStr := query.Get("worker_id"); workerIDStr != "" {
+		workerID, err := uuid.Parse(workerIDStr)
+		if err != nil {
+			return planner.ComplianceFilter{}, fmt.Errorf("worker_id: %w", err)
+		}
+		cf.WorkerID = &workerID
+	}
+	var err error
+	cf.From, err = time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		return planner.ComplianceFilter{}, fmt.Errorf("from: %w", err)
+	}
+	cf.To, err = time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		return planner.ComplianceFilter{}, fmt.Errorf("to: %w", err)
+	}
+	return cf, nil
+}
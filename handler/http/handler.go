@@ -2,26 +2,84 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/auth"
 	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/calendar"
+	"github.com/sp4rd4/wrkpln/planner/export"
+	"github.com/sp4rd4/wrkpln/planner/importer"
+	"github.com/sp4rd4/wrkpln/planner/report"
+	"github.com/sp4rd4/wrkpln/planner/roster"
 )
 
 type PlanningHandler struct {
 	*gin.Engine
-	plan planner.Work
+	plan    planner.Work
+	roster  roster.Generator
+	reports report.Reporter
+	feeds   calendar.Feeds
+	imports importer.Importer
+	exports export.Exporter
+	// defaultTenant is used for requests without tenant header,
+	// header is required if it's empty.
+	defaultTenant  string
+	authenticators []auth.Authenticator
+	// feedTokens issues calendar feed tokens, feed tokens
+	// can't be issued if it isn't set.
+	feedTokens *feedTokens
 }
 
-func New(logger *slog.Logger, plan planner.Work) PlanningHandler {
-	h := PlanningHandler{Engine: gin.New(), plan: plan}
+type feedTokens struct {
+	jwt auth.JWT
+	ttl time.Duration
+}
+
+type Option func(h *PlanningHandler)
+
+func DefaultTenant(tenant string) Option {
+	return func(h *PlanningHandler) {
+		h.defaultTenant = tenant
+	}
+}
+
+// Authenticators enable authentication of all requests,
+// caller is identified with the first of them accepting request credentials.
+func Authenticators(authenticators ...auth.Authenticator) Option {
+	return func(h *PlanningHandler) {
+		h.authenticators = authenticators
+	}
+}
+
+// FeedTokens enables issuing of calendar feed tokens signed by jwt and valid for ttl.
+func FeedTokens(jwt auth.JWT, ttl time.Duration) Option {
+	return func(h *PlanningHandler) {
+		h.feedTokens = &feedTokens{jwt: jwt, ttl: ttl}
+	}
+}
+
+func New(
+	logger *slog.Logger, plan planner.Work, gen roster.Generator, reports report.Reporter, feeds calendar.Feeds,
+	opts ...Option,
+) PlanningHandler {
+	h := PlanningHandler{
+		Engine: gin.New(), plan: plan, roster: gen, reports: reports, feeds: feeds,
+		imports: importer.New(plan), exports: export.New(plan),
+	}
+	for _, opt := range opts {
+		opt(&h)
+	}
 	setRoutes(h, logger)
 	return h
 }
@@ -48,9 +106,38 @@ func (h PlanningHandler) CreateWorker(c *gin.Context) {
 	c.JSON(http.StatusCreated, worker)
 }
 
+func (h PlanningHandler) Worker(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if !ownerCheck(c, id) {
+		return
+	}
+
+	worker, err := h.plan.Worker(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get worker error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, worker)
+}
+
 func (h PlanningHandler) Workers(c *gin.Context) {
-	wf := workersFilter(c.Request.URL.Query())
-	workers, err := h.plan.Workers(c.Request.Context(), wf)
+	wf, err := workersFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	workers, next, err := h.plan.Workers(c.Request.Context(), wf)
 	if err != nil {
 		var planErr planner.Error
 		if errors.As(err, &planErr) {
@@ -63,15 +150,115 @@ func (h PlanningHandler) Workers(c *gin.Context) {
 		return
 	}
 
+	setNextLink(c, next)
 	c.JSON(http.StatusOK, workers)
 }
 
-func workersFilter(query url.Values) planner.WorkersFilter {
+func (h PlanningHandler) UpdateWorker(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	worker := planner.Worker{}
+	if errorReturned := parseJson(c, &worker); !errorReturned {
+		return
+	}
+	worker.ID = id
+
+	worker, err := h.plan.UpdateWorker(c.Request.Context(), worker)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("update worker error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, worker)
+}
+
+func (h PlanningHandler) PatchWorker(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	patch := planner.WorkerPatch{}
+	if errorReturned := parseJson(c, &patch); !errorReturned {
+		return
+	}
+
+	worker, err := h.plan.PatchWorker(c.Request.Context(), id, patch)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("patch worker error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, worker)
+}
+
+func (h PlanningHandler) DeleteWorker(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	err := h.plan.DeleteWorker(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("delete worker error", "error", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func workersFilter(query url.Values) (planner.WorkersFilter, error) {
 	wf := planner.WorkersFilter{}
 	if name := query.Get("name"); name != "" {
 		wf.Name = &name
 	}
-	return wf
+	// skill can be repeated, workers must have all of them
+	wf.Skills = query["skill"]
+	if locationIDStr := query.Get("location_id"); locationIDStr != "" {
+		locationID, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			return planner.WorkersFilter{}, fmt.Errorf("location_id: %w", err)
+		}
+		wf.LocationID = &locationID
+	}
+	var err error
+	wf.Sort, wf.Limit, wf.After, err = pageParams(query)
+	if err != nil {
+		return planner.WorkersFilter{}, err
+	}
+	return wf, nil
 }
 
 func (h PlanningHandler) CreateShift(c *gin.Context) {
@@ -82,6 +269,16 @@ func (h PlanningHandler) CreateShift(c *gin.Context) {
 
 	shift, err := h.plan.CreateShift(c.Request.Context(), shift)
 	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(err, &complianceErr) {
+			handleComplianceError(c, complianceErr)
+			return
+		}
 		var planErr planner.Error
 		if errors.As(err, &planErr) {
 			hadnlePlanningError(c, planErr)
@@ -96,13 +293,46 @@ func (h PlanningHandler) CreateShift(c *gin.Context) {
 	c.JSON(http.StatusCreated, shift)
 }
 
+func (h PlanningHandler) Shift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	shift, err := h.plan.Shift(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get shift error", "error", err)
+		return
+	}
+	// open shifts are seen by workers to claim them
+	if !shift.Open() && !ownerCheck(c, *shift.WorkerID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
 func (h PlanningHandler) Shifts(c *gin.Context) {
 	sf, err := shiftsFilter(c.Request.URL.Query())
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	shifts, err := h.plan.Shifts(c.Request.Context(), sf)
+	// workers see only their own shifts and open ones to claim
+	if p := principal(c); p.Role == auth.RoleWorker && !sf.Open {
+		if sf.WorkerID != nil && !ownerCheck(c, *sf.WorkerID) {
+			return
+		}
+		sf.WorkerID = &p.WorkerID
+	}
+	shifts, next, err := h.plan.Shifts(c.Request.Context(), sf)
 	if err != nil {
 		var planErr planner.Error
 		if errors.As(err, &planErr) {
@@ -115,9 +345,156 @@ func (h PlanningHandler) Shifts(c *gin.Context) {
 		return
 	}
 
+	setNextLink(c, next)
 	c.JSON(http.StatusOK, shifts)
 }
 
+func (h PlanningHandler) CreateShifts(c *gin.Context) {
+	shifts := []planner.Shift{}
+	if err := json.NewDecoder(c.Request.Body).Decode(&shifts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.createShifts(c, shifts)
+}
+
+func (h PlanningHandler) createShifts(c *gin.Context, shifts []planner.Shift) {
+	// gin validates slices too, but drops indices of invalid items
+	items := []gin.H{}
+	for i, shift := range shifts {
+		err := binding.Validator.ValidateStruct(shift)
+		ve := validator.ValidationErrors{}
+		if errors.As(err, &ve) {
+			msg, err := jsonValidationError(ve)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				slog.Error("validate json error", "error", err)
+				return
+			}
+			items = append(items, gin.H{"index": i, "error": msg})
+		}
+	}
+	if len(items) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid shifts", "items": items})
+		return
+	}
+
+	shifts, err := h.plan.CreateShifts(c.Request.Context(), shifts)
+	if err != nil {
+		var batchErr planner.BatchError
+		if errors.As(err, &batchErr) {
+			handleBatchError(c, batchErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("create shifts error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, shifts)
+}
+
+func (h PlanningHandler) UpdateShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	shift := planner.Shift{}
+	if errorReturned := parseJson(c, &shift); !errorReturned {
+		return
+	}
+	shift.ID = id
+
+	shift, err := h.plan.UpdateShift(c.Request.Context(), shift)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(err, &complianceErr) {
+			handleComplianceError(c, complianceErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("update shift error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+func (h PlanningHandler) PatchShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	patch := planner.ShiftPatch{}
+	if errorReturned := parseJson(c, &patch); !errorReturned {
+		return
+	}
+
+	shift, err := h.plan.PatchShift(c.Request.Context(), id, patch)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(err, &complianceErr) {
+			handleComplianceError(c, complianceErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("patch shift error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+func (h PlanningHandler) DeleteShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	err := h.plan.DeleteShift(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("delete shift error", "error", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 func shiftsFilter(query url.Values) (planner.ShiftsFilter, error) {
 	sf := planner.ShiftsFilter{}
 	if workerIDStr := query.Get("worker_id"); workerIDStr != "" {
@@ -127,6 +504,23 @@ func shiftsFilter(query url.Values) (planner.ShiftsFilter, error) {
 		}
 		sf.WorkerID = &workerID
 	}
+	if openStr := query.Get("open"); openStr != "" {
+		open, err := strconv.ParseBool(openStr)
+		if err != nil {
+			return planner.ShiftsFilter{}, fmt.Errorf("open: %w", err)
+		}
+		if open && sf.WorkerID != nil {
+			return planner.ShiftsFilter{}, errors.New("open: can't be used with worker_id")
+		}
+		sf.Open = open
+	}
+	if locationIDStr := query.Get("location_id"); locationIDStr != "" {
+		locationID, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			return planner.ShiftsFilter{}, fmt.Errorf("location_id: %w", err)
+		}
+		sf.LocationID = &locationID
+	}
 	if dateStr := query.Get("date"); dateStr != "" {
 		date, err := time.Parse(time.RFC3339, dateStr)
 		if err != nil {
@@ -134,16 +528,111 @@ func shiftsFilter(query url.Values) (planner.ShiftsFilter, error) {
 		}
 		sf.Date = &date
 	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return planner.ShiftsFilter{}, fmt.Errorf("from: %w", err)
+		}
+		sf.From = &from
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return planner.ShiftsFilter{}, fmt.Errorf("to: %w", err)
+		}
+		sf.To = &to
+	}
+	if sf.From != nil && sf.To != nil && sf.To.Before(*sf.From) {
+		return planner.ShiftsFilter{}, errors.New("to: before from")
+	}
+	var err error
+	sf.Sort, sf.Limit, sf.After, err = pageParams(query)
+	if err != nil {
+		return planner.ShiftsFilter{}, err
+	}
 	return sf, nil
 }
 
+func pageParams(query url.Values) (planner.Sort, int, *planner.Cursor, error) {
+	sort := planner.ParseSort(query.Get("sort"))
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return planner.Sort{}, 0, nil, fmt.Errorf("limit: must be positive integer")
+		}
+	}
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor, err := planner.ParseCursor(cursorStr)
+		if err != nil {
+			return planner.Sort{}, 0, nil, fmt.Errorf("cursor: %w", err)
+		}
+		return sort, limit, &cursor, nil
+	}
+	return sort, limit, nil, nil
+}
+
+// setNextLink adds Link header pointing to the next page
+// with the same query parameters and updated cursor.
+func setNextLink(c *gin.Context, next *planner.Cursor) {
+	if next == nil {
+		return
+	}
+	query := c.Request.URL.Query()
+	query.Set("cursor", next.String())
+	link := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	c.Header("Link", "<"+link.String()+">; rel=\"next\"")
+}
+
 func hadnlePlanningError(c *gin.Context, err planner.Error) {
 	switch err {
-	case planner.ErrDayAlreadyBooked:
+	case planner.ErrDayAlreadyBooked, planner.ErrShiftsOverlap, planner.ErrRestTooShort,
+		planner.ErrWorkerUnavailable, planner.ErrInvalidTransition, planner.ErrShiftTaken,
+		planner.ErrUnqualified, planner.ErrLocationInUse, planner.ErrNonCompliant:
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 	case planner.ErrNoRecord:
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case planner.ErrInvalidFilter, planner.ErrInvalidRange:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case planner.ErrInvalidShift, planner.ErrInvalidBatch, planner.ErrInvalidSwap:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		// response must not be left empty by errors without status
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("unmapped planning error", "error", err)
+	}
+}
+
+func handleConflictError(c *gin.Context, err planner.ConflictError) {
+	c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "shift": err.Shift})
+}
+
+func handleComplianceError(c *gin.Context, err planner.ComplianceError) {
+	c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "violations": err.Violations})
+}
+
+func handleBatchError(c *gin.Context, err planner.BatchError) {
+	items := make([]gin.H, 0, len(err.Items))
+	for _, item := range err.Items {
+		resp := gin.H{"index": item.Index, "error": item.Err.Error()}
+		var conflictErr planner.ConflictError
+		if errors.As(item.Err, &conflictErr) {
+			// shifts of the batch are not created, so they are referenced by index
+			if item.ConflictIndex != nil {
+				resp["error"] = conflictErr.Reason.Error()
+				resp["conflict_index"] = *item.ConflictIndex
+			} else {
+				resp["shift"] = conflictErr.Shift
+			}
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(item.Err, &complianceErr) {
+			resp["violations"] = complianceErr.Violations
+		}
+		items = append(items, resp)
 	}
+	c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "items": items})
 }
 
 func jsonValidationError(errs validator.ValidationErrors) (string, error) {
@@ -182,3 +671,12 @@ func parseJson(c *gin.Context, obj any) bool {
 	}
 	return true
 }
+
+func parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Errorf("id: %w", err).Error()})
+		return uuid.UUID{}, false
+	}
+	return id, true
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/importer"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// maxImportBytes limits size of imported files.
+const maxImportBytes = 10 << 20
+
+// Import creates workers or shifts from CSV or XLSX file of request body.
+func (h PlanningHandler) Import(c *gin.Context) {
+	kind := importer.Kind(c.Param("kind"))
+	if kind != importer.Workers && kind != importer.Shifts {
+		c.JSON(http.StatusNotFound, gin.H{"error": "404 page not found"})
+		return
+	}
+	format := importer.Format(c.Query("format"))
+	if format == "" {
+		switch c.ContentType() {
+		case "text/csv":
+			format = importer.CSV
+		case xlsxContentType:
+			format = importer.XLSX
+		default:
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "415 unsupported media type"})
+			return
+		}
+	}
+	dryRun := false
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run: " + err.Error()})
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	report, err := h.imports.Import(c.Request.Context(), kind, format, body, dryRun)
+	if err != nil {
+		// oversized file is also an invalid one, so it's checked first
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "413 request entity too large"})
+			return
+		}
+		// file errors tell which column or line is wrong
+		if errors.Is(err, importer.ErrInvalidFile) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("import error", "error", err)
+		return
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		c.JSON(http.StatusUnprocessableEntity, report)
+	case dryRun:
+		c.JSON(http.StatusOK, report)
+	default:
+		c.JSON(http.StatusCreated, report)
+	}
+}
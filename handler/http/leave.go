@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+func (h PlanningHandler) RequestLeave(c *gin.Context) {
+	workerID, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if !ownerCheck(c, workerID) {
+		return
+	}
+	leave := planner.LeaveRequest{}
+	if errorReturned := parseJson(c, &leave); !errorReturned {
+		return
+	}
+	leave.WorkerID = workerID
+
+	leave, err := h.plan.RequestLeave(c.Request.Context(), leave)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("request leave error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, leave)
+}
+
+func (h PlanningHandler) Leave(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	leave, err := h.plan.Leave(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get leave error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leave)
+}
+
+func (h PlanningHandler) Leaves(c *gin.Context) {
+	lf, err := leavesFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaves, err := h.plan.Leaves(c.Request.Context(), lf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("list leaves error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leaves)
+}
+
+func leavesFilter(query url.Values) (planner.LeavesFilter, error) {
+	lf := planner.LeavesFilter{}
+	if workerIDStr := query.Get("worker_id"); workerIDStr != "" {
+		workerID, err := uuid.Parse(workerIDStr)
+		if err != nil {
+			return planner.LeavesFilter{}, fmt.Errorf("worker_id: %w", err)
+		}
+		lf.WorkerID = &workerID
+	}
+	if statusStr := query.Get("status"); statusStr != "" {
+		status := planner.LeaveStatus(statusStr)
+		lf.Status = &status
+	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return planner.LeavesFilter{}, fmt.Errorf("from: %w", err)
+		}
+		lf.From = &from
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return planner.LeavesFilter{}, fmt.Errorf("to: %w", err)
+		}
+		lf.To = &to
+	}
+	if lf.From != nil && lf.To != nil && lf.To.Before(*lf.From) {
+		return planner.LeavesFilter{}, errors.New("to: before from")
+	}
+	return lf, nil
+}
+
+func (h PlanningHandler) ApproveLeave(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	release := false
+	if releaseStr := c.Query("release_shifts"); releaseStr != "" {
+		var err error
+		release, err = strconv.ParseBool(releaseStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Errorf("release_shifts: %w", err).Error()})
+			return
+		}
+	}
+
+	approval, err := h.plan.ApproveLeave(c.Request.Context(), id, release)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("approve leave error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"leave":    approval.Leave,
+		"shifts":   approval.Shifts,
+		"released": approval.Released,
+	})
+}
+
+func (h PlanningHandler) RejectLeave(c *gin.Context) {
+	h.moveLeave(c, "reject", h.plan.RejectLeave)
+}
+
+func (h PlanningHandler) CancelLeave(c *gin.Context) {
+	h.moveLeave(c, "cancel", h.plan.CancelLeave)
+}
+
+func (h PlanningHandler) moveLeave(
+	c *gin.Context, action string, move func(context.Context, uuid.UUID) (planner.LeaveRequest, error),
+) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	leave, err := move(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error(action+" leave error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leave)
+}
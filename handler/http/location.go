@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+func (h PlanningHandler) CreateLocation(c *gin.Context) {
+	location := planner.Location{}
+	if errorReturned := parseJson(c, &location); !errorReturned {
+		return
+	}
+
+	location, err := h.plan.CreateLocation(c.Request.Context(), location)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("create location error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, location)
+}
+
+func (h PlanningHandler) Location(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	location, err := h.plan.Location(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get location error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+func (h PlanningHandler) Locations(c *gin.Context) {
+	locations, err := h.plan.Locations(c.Request.Context())
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("list locations error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+func (h PlanningHandler) UpdateLocation(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	location := planner.Location{}
+	if errorReturned := parseJson(c, &location); !errorReturned {
+		return
+	}
+	location.ID = id
+
+	location, err := h.plan.UpdateLocation(c.Request.Context(), location)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("update location error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+func (h PlanningHandler) DeleteLocation(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	err := h.plan.DeleteLocation(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("delete location error", "error", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
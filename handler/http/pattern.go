@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+type expandRequest struct {
+	From time.Time `json:"from" binding:"required"`
+	To   time.Time `json:"to" binding:"required"`
+}
+
+func (h PlanningHandler) CreatePattern(c *gin.Context) {
+	pattern := planner.ShiftPattern{}
+	if errorReturned := parseJson(c, &pattern); !errorReturned {
+		return
+	}
+
+	pattern, err := h.plan.CreatePattern(c.Request.Context(), pattern)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("create pattern error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, pattern)
+}
+
+func (h PlanningHandler) Pattern(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	pattern, err := h.plan.Pattern(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get pattern error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pattern)
+}
+
+func (h PlanningHandler) Patterns(c *gin.Context) {
+	pf, err := patternsFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	patterns, err := h.plan.Patterns(c.Request.Context(), pf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("list patterns error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, patterns)
+}
+
+func patternsFilter(query url.Values) (planner.PatternsFilter, error) {
+	pf := planner.PatternsFilter{}
+	if workerIDStr := query.Get("worker_id"); workerIDStr != "" {
+		workerID, err := uuid.Parse(workerIDStr)
+		if err != nil {
+			return planner.PatternsFilter{}, fmt.Errorf("worker_id: %w", err)
+		}
+		pf.WorkerID = &workerID
+	}
+	return pf, nil
+}
+
+func (h PlanningHandler) DeletePattern(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	err := h.plan.DeletePattern(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("delete pattern error", "error", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h PlanningHandler) ExpandPattern(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	req := expandRequest{}
+	if errorReturned := parseJson(c, &req); !errorReturned {
+		return
+	}
+
+	expansion, err := h.plan.ExpandPattern(c.Request.Context(), id, req.From, req.To)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("expand pattern error", "error", err)
+		return
+	}
+
+	created := expansion.Created
+	if created == nil {
+		created = []planner.Shift{}
+	}
+	skipped := make([]gin.H, 0, len(expansion.Skipped))
+	for _, s := range expansion.Skipped {
+		resp := gin.H{"date": s.Date, "error": s.Err.Error()}
+		var conflictErr planner.ConflictError
+		if errors.As(s.Err, &conflictErr) {
+			resp["shift"] = conflictErr.Shift
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(s.Err, &complianceErr) {
+			resp["violations"] = complianceErr.Violations
+		}
+		skipped = append(skipped, resp)
+	}
+	c.JSON(http.StatusCreated, gin.H{"created": created, "skipped": skipped})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/report"
+)
+
+// HoursReport sums scheduled hours of workers in the date range
+// split into regular and overtime.
+func (h PlanningHandler) HoursReport(c *gin.Context) {
+	hf, err := hoursFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hours, err := h.reports.Hours(c.Request.Context(), hf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("hours report error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+func hoursFilter(query url.Values) (report.HoursFilter, error) {
+	hf := report.HoursFilter{}
+	if workerIDStr := query.Get("worker_id"); workerIDStr != "" {
+		workerID, err := uuid.Parse(workerIDStr)
+		if err != nil {
+			return report.HoursFilter{}, fmt.Errorf("worker_id: %w", err)
+		}
+		hf.WorkerID = &workerID
+	}
+	if locationIDStr := query.Get("location_id"); locationIDStr != "" {
+		locationID, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			return report.HoursFilter{}, fmt.Errorf("location_id: %w", err)
+		}
+		hf.LocationID = &locationID
+	}
+	var err error
+	hf.From, err = time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		return report.HoursFilter{}, fmt.Errorf("from: %w", err)
+	}
+	hf.To, err = time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		return report.HoursFilter{}, fmt.Errorf("to: %w", err)
+	}
+	return hf, nil
+}
+
+// Coverage counts workers on shift for every hour of the date range
+// and compares them with staffing targets.
+func (h PlanningHandler) Coverage(c *gin.Context) {
+	cf, err := coverageFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slots, err := h.reports.Coverage(c.Request.Context(), cf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("coverage error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+func coverageFilter(query url.Values) (report.CoverageFilter, error) {
+	cf := report.CoverageFilter{Skill: query.Get("skill")}
+	if locationIDStr := query.Get("location_id"); locationIDStr != "" {
+		locationID, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			return report.CoverageFilter{}, fmt.Errorf("location_id: %w", err)
+		}
+		cf.LocationID = &locationID
+	}
+	var err error
+	cf.From, err = time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		return report.CoverageFilter{}, fmt.Errorf("from: %w", err)
+	}
+	cf.To, err = time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		return report.CoverageFilter{}, fmt.Errorf("to: %w", err)
+	}
+	return cf, nil
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/export"
+	"github.com/sp4rd4/wrkpln/planner/roster"
+)
+
+func (h PlanningHandler) GenerateRoster(c *gin.Context) {
+	req := roster.Request{}
+	if errorReturned := parseJson(c, &req); !errorReturned {
+		return
+	}
+
+	proposal, err := h.roster.Generate(c.Request.Context(), req)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("generate roster error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, proposal)
+}
+
+// CommitRoster creates shifts of the proposal returned by GenerateRoster
+// in one transaction, so proposal can be sent back as is.
+func (h PlanningHandler) CommitRoster(c *gin.Context) {
+	proposal := roster.Proposal{}
+	if errorReturned := parseJson(c, &proposal); !errorReturned {
+		return
+	}
+	h.createShifts(c, proposal.Shifts)
+}
+
+// rosterFormats maps offered media types to export formats,
+// JSON is offered first and served without export.
+var rosterFormats = map[string]export.Format{
+	"text/csv":      export.CSV,
+	xlsxContentType: export.XLSX,
+	gin.MIMEHTML:    export.HTML,
+}
+
+// Roster returns shifts of the date range as a grid of workers and days,
+// format is chosen with format parameter or Accept header.
+func (h PlanningHandler) Roster(c *gin.Context) {
+	ef, err := exportFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := export.Format(c.Query("format"))
+	if format == "" {
+		mediaType := c.NegotiateFormat(gin.MIMEJSON, "text/csv", xlsxContentType, gin.MIMEHTML)
+		if mediaType == "" {
+			c.JSON(http.StatusNotAcceptable, gin.H{"error": "406 not acceptable"})
+			return
+		}
+		format = rosterFormats[mediaType]
+	} else if format != "json" && format.ContentType() == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format: unknown format " + string(format)})
+		return
+	}
+
+	grid, err := h.exports.Roster(c.Request.Context(), ef)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("export roster error", "error", err)
+		return
+	}
+	if format == "" || format == "json" {
+		c.JSON(http.StatusOK, grid)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	disposition := "attachment"
+	if format == export.HTML {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`%s; filename="roster.%s"`, disposition, format))
+	c.Status(http.StatusOK)
+	if err := grid.Write(c.Writer, format); err != nil {
+		slog.Error("roster write error", "error", err)
+	}
+}
+
+func exportFilter(query url.Values) (export.Filter, error) {
+	ef := export.Filter{}
+	if locationIDStr := query.Get("location_id"); locationIDStr != "" {
+		locationID, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			return export.Filter{}, fmt.Errorf("location_id: %w", err)
+		}
+		ef.LocationID = &locationID
+	}
+	var err error
+	ef.From, err = time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		return export.Filter{}, fmt.Errorf("from: %w", err)
+	}
+	ef.To, err = time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		return export.Filter{}, fmt.Errorf("to: %w", err)
+	}
+	return ef, nil
+}
@@ -6,15 +6,85 @@ import (
 
 	"github.com/gin-gonic/gin"
 	sloggin "github.com/samber/slog-gin"
+	"github.com/sp4rd4/wrkpln/auth"
+	"github.com/sp4rd4/wrkpln/planner"
 )
 
 func setRoutes(handler PlanningHandler, logger *slog.Logger) {
-	handler.Use(sloggin.New(logger), gin.Recovery())
-	handler.POST("/worker", ContentTypeCheck, handler.CreateWorker)
-	handler.GET("/workers", handler.Workers)
+	handler.Use(
+		FeedToken, sloggin.New(logger), gin.Recovery(),
+		Authenticate(handler.authenticators), TenantCheck(handler.defaultTenant),
+	)
+	admin := Allow(auth.RoleAdmin)
+	planners := Allow(auth.RoleAdmin, auth.RolePlanner)
+	everyone := Allow(auth.RoleAdmin, auth.RolePlanner, auth.RoleWorker)
 
-	handler.POST("/shift", ContentTypeCheck, handler.CreateShift)
-	handler.GET("/shifts", handler.Shifts)
+	handler.POST("/worker", planners, ContentTypeCheck, handler.CreateWorker)
+	handler.GET("/worker/:id", everyone, handler.Worker)
+	handler.GET("/worker/:id/shifts.ics", everyone, handler.WorkerCalendar)
+	handler.PUT("/worker/:id", planners, ContentTypeCheck, handler.UpdateWorker)
+	handler.PATCH("/worker/:id", planners, ContentTypeCheck, handler.PatchWorker)
+	handler.DELETE("/worker/:id", admin, handler.DeleteWorker)
+	handler.GET("/workers", planners, handler.Workers)
+
+	handler.POST("/location", admin, ContentTypeCheck, handler.CreateLocation)
+	handler.GET("/location/:id", planners, handler.Location)
+	handler.PUT("/location/:id", admin, ContentTypeCheck, handler.UpdateLocation)
+	handler.DELETE("/location/:id", admin, handler.DeleteLocation)
+	handler.GET("/locations", planners, handler.Locations)
+
+	handler.POST("/worker/:id/availability", planners, ContentTypeCheck, handler.CreateAvailability)
+	handler.GET("/worker/:id/availability", planners, handler.Availabilities)
+	handler.PUT("/availability/:id", planners, ContentTypeCheck, handler.UpdateAvailability)
+	handler.DELETE("/availability/:id", planners, handler.DeleteAvailability)
+	handler.POST("/worker/:id/unavailability", planners, ContentTypeCheck, handler.CreateUnavailability)
+	handler.GET("/worker/:id/unavailability", planners, handler.Unavailabilities)
+	handler.PUT("/unavailability/:id", planners, ContentTypeCheck, handler.UpdateUnavailability)
+	handler.DELETE("/unavailability/:id", planners, handler.DeleteUnavailability)
+
+	handler.POST("/worker/:id/leave", everyone, ContentTypeCheck, handler.RequestLeave)
+	handler.GET("/leave/:id", planners, handler.Leave)
+	handler.GET("/leaves", planners, handler.Leaves)
+	handler.POST("/leave/:id/approve", planners, handler.ApproveLeave)
+	handler.POST("/leave/:id/reject", planners, handler.RejectLeave)
+	handler.POST("/leave/:id/cancel", planners, handler.CancelLeave)
+
+	handler.POST("/shift", planners, ContentTypeCheck, handler.CreateShift)
+	handler.GET("/shift/:id", everyone, handler.Shift)
+	handler.PUT("/shift/:id", planners, ContentTypeCheck, handler.UpdateShift)
+	handler.PATCH("/shift/:id", planners, ContentTypeCheck, handler.PatchShift)
+	handler.DELETE("/shift/:id", planners, handler.DeleteShift)
+	handler.GET("/shifts", everyone, handler.Shifts)
+	handler.GET("/shifts.ics", planners, handler.Calendar)
+	handler.POST("/feed-token", everyone, handler.FeedToken)
+	handler.POST("/shifts/batch", planners, ContentTypeCheck, handler.CreateShifts)
+	handler.POST("/shift/:id/claim", everyone, handler.ClaimShift)
+	handler.POST("/shift/:id/transfer", planners, ContentTypeCheck, handler.TransferShift)
+	handler.POST("/shifts/swap", planners, ContentTypeCheck, handler.SwapShifts)
+
+	handler.POST("/swap", everyone, ContentTypeCheck, handler.RequestSwap)
+	handler.GET("/swap/:id", planners, handler.Swap)
+	handler.GET("/swaps", planners, handler.Swaps)
+	handler.POST("/swap/:id/accept", everyone, handler.AcceptSwap)
+	handler.POST("/swap/:id/decline", everyone, handler.DeclineSwap)
+	handler.POST("/swap/:id/cancel", planners, handler.CancelSwap)
+
+	handler.POST("/pattern", planners, ContentTypeCheck, handler.CreatePattern)
+	handler.GET("/pattern/:id", planners, handler.Pattern)
+	handler.DELETE("/pattern/:id", planners, handler.DeletePattern)
+	handler.POST("/pattern/:id/expand", planners, ContentTypeCheck, handler.ExpandPattern)
+	handler.GET("/patterns", planners, handler.Patterns)
+
+	handler.GET("/roster", planners, handler.Roster)
+	handler.POST("/roster/generate", planners, ContentTypeCheck, handler.GenerateRoster)
+	handler.POST("/roster/commit", planners, ContentTypeCheck, handler.CommitRoster)
+
+	handler.POST("/import/:kind", planners, handler.Import)
+
+	handler.GET("/compliance", planners, handler.Compliance)
+	handler.GET("/reports/hours", planners, handler.HoursReport)
+	handler.GET("/coverage", planners, handler.Coverage)
+	handler.GET("/audit", admin, handler.AuditEntries)
 
 	handler.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "404 page not found"})
@@ -24,6 +94,40 @@ func setRoutes(handler PlanningHandler, logger *slog.Logger) {
 	})
 }
 
+const TenantHeader = "X-Tenant-ID"
+
+// TenantCheck puts tenant of the caller into request context,
+// only admins not bound to a tenant can set it with the header,
+// defaultTenant is used if header is missing.
+func TenantCheck(defaultTenant string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant := c.GetHeader(TenantHeader)
+		p := principal(c)
+		if p.Role != auth.RoleAdmin && p.Tenant == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "403 forbidden"})
+			return
+		}
+		if bound := p.Tenant; bound != "" {
+			if tenant != "" && tenant != bound {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "403 forbidden"})
+				return
+			}
+			tenant = bound
+		}
+		if tenant == "" {
+			tenant = defaultTenant
+		}
+		if !planner.ValidTenant(tenant) {
+			c.AbortWithStatusJSON(
+				http.StatusBadRequest,
+				gin.H{"error": "missing or invalid " + TenantHeader + " header"},
+			)
+			return
+		}
+		c.Request = c.Request.WithContext(planner.WithTenant(c.Request.Context(), tenant))
+	}
+}
+
 func ContentTypeCheck(c *gin.Context) {
 	if c.ContentType() != "application/json" {
 		c.AbortWithStatusJSON(
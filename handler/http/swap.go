@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/auth"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+type assignRequest struct {
+	WorkerID uuid.UUID `json:"worker_id" binding:"required"`
+}
+
+type swapShiftsRequest struct {
+	ShiftID      uuid.UUID `json:"shift_id" binding:"required"`
+	OtherShiftID uuid.UUID `json:"other_shift_id" binding:"required"`
+}
+
+func (h PlanningHandler) TransferShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	req := assignRequest{}
+	if errorReturned := parseJson(c, &req); !errorReturned {
+		return
+	}
+
+	shift, err := h.plan.TransferShift(c.Request.Context(), id, req.WorkerID)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(err, &complianceErr) {
+			handleComplianceError(c, complianceErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("transfer shift error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+func (h PlanningHandler) ClaimShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	// workers claim shifts for themselves, worker of the body is ignored
+	workerID := principal(c).WorkerID
+	if principal(c).Role != auth.RoleWorker {
+		if ContentTypeCheck(c); c.IsAborted() {
+			return
+		}
+		req := assignRequest{}
+		if errorReturned := parseJson(c, &req); !errorReturned {
+			return
+		}
+		workerID = req.WorkerID
+	}
+
+	shift, err := h.plan.ClaimShift(c.Request.Context(), id, workerID)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(err, &complianceErr) {
+			handleComplianceError(c, complianceErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("claim shift error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+func (h PlanningHandler) SwapShifts(c *gin.Context) {
+	req := swapShiftsRequest{}
+	if errorReturned := parseJson(c, &req); !errorReturned {
+		return
+	}
+
+	shifts, err := h.plan.SwapShifts(c.Request.Context(), req.ShiftID, req.OtherShiftID)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(err, &complianceErr) {
+			handleComplianceError(c, complianceErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("swap shifts error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shifts)
+}
+
+func (h PlanningHandler) RequestSwap(c *gin.Context) {
+	swap := planner.SwapRequest{}
+	if errorReturned := parseJson(c, &swap); !errorReturned {
+		return
+	}
+	if !h.shiftOwnerCheck(c, swap.ShiftID) {
+		return
+	}
+
+	swap, err := h.plan.RequestSwap(c.Request.Context(), swap)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("request swap error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, swap)
+}
+
+func (h PlanningHandler) Swap(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	swap, err := h.plan.Swap(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get swap error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, swap)
+}
+
+func (h PlanningHandler) Swaps(c *gin.Context) {
+	sf, err := swapsFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	swaps, err := h.plan.Swaps(c.Request.Context(), sf)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("list swaps error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, swaps)
+}
+
+func swapsFilter(query url.Values) (planner.SwapsFilter, error) {
+	sf := planner.SwapsFilter{}
+	if workerIDStr := query.Get("worker_id"); workerIDStr != "" {
+		workerID, err := uuid.Parse(workerIDStr)
+		if err != nil {
+			return planner.SwapsFilter{}, fmt.Errorf("worker_id: %w", err)
+		}
+		sf.WorkerID = &workerID
+	}
+	if statusStr := query.Get("status"); statusStr != "" {
+		status := planner.SwapStatus(statusStr)
+		sf.Status = &status
+	}
+	return sf, nil
+}
+
+func (h PlanningHandler) AcceptSwap(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if !h.recipientCheck(c, id) {
+		return
+	}
+
+	swap, shifts, err := h.plan.AcceptSwap(c.Request.Context(), id)
+	if err != nil {
+		var conflictErr planner.ConflictError
+		if errors.As(err, &conflictErr) {
+			handleConflictError(c, conflictErr)
+			return
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(err, &complianceErr) {
+			handleComplianceError(c, complianceErr)
+			return
+		}
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("accept swap error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"swap": swap, "shifts": shifts})
+}
+
+func (h PlanningHandler) DeclineSwap(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if !h.recipientCheck(c, id) {
+		return
+	}
+	h.moveSwap(c, id, "decline", h.plan.DeclineSwap)
+}
+
+func (h PlanningHandler) CancelSwap(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	h.moveSwap(c, id, "cancel", h.plan.CancelSwap)
+}
+
+func (h PlanningHandler) moveSwap(
+	c *gin.Context, id uuid.UUID, action string, move func(context.Context, uuid.UUID) (planner.SwapRequest, error),
+) {
+	swap, err := move(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error(action+" swap error", "error", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, swap)
+}
+
+// shiftOwnerCheck aborts request of caller with worker role
+// if the shift isn't assigned to the caller.
+func (h PlanningHandler) shiftOwnerCheck(c *gin.Context, id uuid.UUID) bool {
+	if principal(c).Role != auth.RoleWorker {
+		return true
+	}
+	shift, err := h.plan.Shift(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get shift error", "error", err)
+		return false
+	}
+	// open shift has no owner
+	var owner uuid.UUID
+	if !shift.Open() {
+		owner = *shift.WorkerID
+	}
+	return ownerCheck(c, owner)
+}
+
+// recipientCheck aborts request of caller with worker role
+// if the swap is offered to another worker.
+func (h PlanningHandler) recipientCheck(c *gin.Context, id uuid.UUID) bool {
+	if principal(c).Role != auth.RoleWorker {
+		return true
+	}
+	swap, err := h.plan.Swap(c.Request.Context(), id)
+	if err != nil {
+		var planErr planner.Error
+		if errors.As(err, &planErr) {
+			hadnlePlanningError(c, planErr)
+			return false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		slog.Error("get swap error", "error", err)
+		return false
+	}
+	return ownerCheck(c, swap.ToWorkerID)
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"os/user"
 	"syscall"
 
 	"github.com/caarlos0/env"
 	"github.com/sp4rd4/wrkpln/config"
+	"github.com/sp4rd4/wrkpln/planner/importer"
 	"github.com/sp4rd4/wrkpln/service"
 )
 
@@ -29,11 +34,48 @@ func main() {
 	logger := slog.New(h)
 	slog.SetDefault(logger)
 
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := importFile(ctx, cfg, os.Args[2:]); err != nil {
+			slog.Error("import error", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := service.Start(ctx, logger, cfg); err != nil {
 		slog.Error("server error", "error", err)
 	}
 }
 
+// importFile runs import command:
+//
+//	wrkpln import [-dry-run] [-tenant tenant] [-actor actor] workers|shifts file
+func importFile(ctx context.Context, cfg config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	opts := service.ImportOptions{}
+	// changes are audited as made by the user running the command unless told otherwise
+	actor := ""
+	if u, err := user.Current(); err == nil {
+		actor = u.Username
+	}
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "validate file without storing records")
+	flags.StringVar(&opts.Tenant, "tenant", "", "tenant of records, DEFAULT_TENANT is used if empty")
+	flags.StringVar(&opts.Actor, "actor", actor, "author of records in the audit log")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: wrkpln import [-dry-run] [-tenant tenant] [-actor actor] workers|shifts file")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("expected kind and file arguments")
+	}
+	opts.Kind, opts.Path = importer.Kind(flags.Arg(0)), flags.Arg(1)
+	return service.Import(ctx, cfg, opts, os.Stdout)
+}
+
 func setupGracefulShutdown(stop func()) {
 	signalChannel := make(chan os.Signal, 1)
 	signal.Notify(signalChannel, os.Interrupt, syscall.SIGTERM)
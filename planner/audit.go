@@ -0,0 +1,120 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry records a change of the entity, entries are never changed or deleted.
+type AuditEntry struct {
+	ID       uuid.UUID `json:"id"`
+	Time     time.Time `json:"time" gorm:"column:recorded_at"`
+	Actor    string    `json:"actor"`
+	Entity   string    `json:"entity"`
+	EntityID uuid.UUID `json:"entity_id"`
+	Action   string    `json:"action"`
+	// Before is null for created entities, After is null for deleted ones.
+	Before   json.RawMessage `json:"before" gorm:"serializer:json"`
+	After    json.RawMessage `json:"after" gorm:"serializer:json"`
+	TenantID string          `json:"-"`
+}
+
+type AuditFilter struct {
+	Entity   *string    `json:"entity"`
+	EntityID *uuid.UUID `json:"entity_id"`
+	Actor    *string    `json:"actor"`
+	// From and To are inclusive bounds of entry time.
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+
+	// Entries are always sorted by time with ID as a tie-breaker.
+	// Limit of zero means no limit for repository.
+	Limit int     `json:"limit"`
+	After *Cursor `json:"after"`
+}
+
+// Revision is the last change of an entity found in the audit log,
+// Sequence is a number of its changes after it was created.
+type Revision struct {
+	EntityID uuid.UUID `json:"entity_id"`
+	Modified time.Time `json:"modified"`
+	Sequence int       `json:"sequence"`
+}
+
+// SystemActor is recorded for changes made without actor in context.
+const SystemActor = "system"
+
+type actorKey struct{}
+
+// WithActor returns context of the actor making changes.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns actor of the context, SystemActor is returned if it isn't set.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+// AuditEntries returns a page of entries and a cursor of the next page,
+// the cursor is nil for the last page.
+func (w Work) AuditEntries(ctx context.Context, filter AuditFilter) ([]AuditEntry, *Cursor, error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, nil, ErrInvalidRange
+	}
+	filter.Limit = pageLimit(filter.Limit)
+	// one extra record is requested to find out if there is a next page
+	filter.Limit++
+
+	entries, err := w.repo.AuditEntries(ctx, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list audit entries: %w", err)
+	}
+	if len(entries) < filter.Limit {
+		return entries, nil, nil
+	}
+	entries = entries[:len(entries)-1]
+	last := entries[len(entries)-1]
+	return entries, &Cursor{Date: last.Time, ID: last.ID}, nil
+}
+
+// audit records change of the entity within the transaction making it,
+// before is nil for created entities and after is nil for deleted ones.
+func (w Work) audit(
+	ctx context.Context, repo Repository, entity, action string, id uuid.UUID, before, after any,
+) error {
+	// entry ID isn't taken from w.uuid, so IDs of entities don't depend on auditing
+	entry := AuditEntry{
+		ID: uuid.New(), Time: w.now().UTC(), Actor: ActorFrom(ctx),
+		Entity: entity, EntityID: id, Action: action,
+	}
+	var err error
+	if entry.Before, err = auditState(before); err != nil {
+		return fmt.Errorf("audit %s %s: %w", entity, action, err)
+	}
+	if entry.After, err = auditState(after); err != nil {
+		return fmt.Errorf("audit %s %s: %w", entity, action, err)
+	}
+	if err := repo.CreateAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("creating audit entry: %w", err)
+	}
+	return nil
+}
+
+func auditState(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal state: %w", err)
+	}
+	return data, nil
+}
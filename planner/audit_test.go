@@ -0,0 +1,83 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Date(2025, 11, 3, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	id := uuid.New()
+	worker := planner.Worker{ID: id, Name: "Buddy Guy", Skills: []string{"forklift"}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	plan := planner.New(repo, planner.TimeSource(func() time.Time { return now }))
+	var entry planner.AuditEntry
+	gomock.InOrder(
+		repo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Worker(gomock.Any(), id).Return(worker, nil),
+		repo.EXPECT().UpdateWorker(gomock.Any(), gomock.Any()).Return(nil),
+		repo.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e planner.AuditEntry) error {
+			entry = e
+			return nil
+		}),
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Pattern(ctx, id).Return(planner.ShiftPattern{ID: id}, nil),
+		repo.EXPECT().DeletePattern(ctx, id).Return(nil),
+		repo.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(assert.AnError),
+	)
+
+	_, err := plan.PatchWorker(planner.WithActor(ctx, "alice"), id, planner.WorkerPatch{Name: ptr("Muddy Waters")})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, entry.ID)
+	assert.Equal(t, "alice", entry.Actor)
+	assert.Equal(t, now.UTC(), entry.Time)
+	assert.Equal(t, "worker", entry.Entity)
+	assert.Equal(t, "update", entry.Action)
+	assert.Equal(t, id, entry.EntityID)
+	assert.JSONEq(t, `{"id":"`+id.String()+`","name":"Buddy Guy","skills":["forklift"]}`, string(entry.Before))
+	assert.JSONEq(t, `{"id":"`+id.String()+`","name":"Muddy Waters","skills":["forklift"]}`, string(entry.After))
+
+	// change is rolled back together with its audit entry
+	err = plan.DeletePattern(ctx, id)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, planner.SystemActor, planner.ActorFrom(ctx))
+}
+
+func TestAuditEntries(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	entries := []planner.AuditEntry{
+		{ID: uuid.New(), Time: day},
+		{ID: uuid.New(), Time: day.Add(time.Hour)},
+		{ID: uuid.New(), Time: day.Add(2 * time.Hour)},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	plan := planner.New(repo)
+	repo.EXPECT().AuditEntries(ctx, planner.AuditFilter{Limit: 3}).Return(entries, nil)
+	result, cursor, err := plan.AuditEntries(ctx, planner.AuditFilter{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, entries[:2], result)
+	assert.Equal(t, &planner.Cursor{Date: entries[1].Time, ID: entries[1].ID}, cursor)
+
+	_, _, err = plan.AuditEntries(ctx, planner.AuditFilter{From: ptr(day), To: ptr(day.Add(-time.Hour))})
+	assert.ErrorIs(t, err, planner.ErrInvalidRange)
+}
@@ -0,0 +1,235 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Availability is a weekly window when worker can work.
+// Worker without availability windows can work at any time.
+type Availability struct {
+	ID       uuid.UUID `json:"id"`
+	WorkerID uuid.UUID `json:"worker_id"`
+	Weekdays Weekdays  `json:"weekdays" binding:"required"`
+	Start    Clock     `json:"start" gorm:"column:start_minute" binding:"gte=0,lte=1439"`
+	// End before Start means that window ends on the next day.
+	End      Clock  `json:"end" gorm:"column:end_minute" binding:"gte=1,lte=1440,nefield=Start"`
+	TenantID string `json:"-"`
+}
+
+type AvailabilityFilter struct {
+	WorkerID *uuid.UUID `json:"worker_id"`
+}
+
+// Covers reports whether shift fits into one of the window days.
+func (a Availability) Covers(shift Shift) bool {
+	// overnight window of the previous day can cover early shift
+	for _, date := range []time.Time{shift.Date, shift.Date.AddDate(0, 0, -1)} {
+		if !a.Weekdays.Has(date.Weekday()) {
+			continue
+		}
+		window := Shift{Date: date, Start: a.Start, End: a.End}
+		if !shift.StartTime().Before(window.StartTime()) && !shift.EndTime().After(window.EndTime()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unavailability is a time range when worker can't work, e.g. leave or sick days.
+type Unavailability struct {
+	ID       uuid.UUID `json:"id"`
+	WorkerID uuid.UUID `json:"worker_id"`
+	From     time.Time `json:"from" gorm:"column:starts_at" binding:"required"`
+	To       time.Time `json:"to" gorm:"column:ends_at" binding:"required,gtfield=From"`
+	Reason   string    `json:"reason"`
+	TenantID string    `json:"-"`
+}
+
+// normalize converts range to UTC, so stored times are comparable.
+func (u *Unavailability) normalize() {
+	u.From = u.From.UTC()
+	u.To = u.To.UTC()
+}
+
+type UnavailabilityFilter struct {
+	WorkerID *uuid.UUID `json:"worker_id"`
+	// From and To select unavailabilities overlapping with the range.
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+}
+
+// CheckAvailability verifies that shift doesn't overlap with unavailabilities
+// and fits into one of availability windows if there are any.
+func CheckAvailability(shift Shift, windows []Availability, absences []Unavailability) error {
+	for _, a := range absences {
+		if a.From.Before(shift.EndTime()) && shift.StartTime().Before(a.To) {
+			return fmt.Errorf("unavailable %s: %w", a.ID, ErrWorkerUnavailable)
+		}
+	}
+	if len(windows) == 0 {
+		return nil
+	}
+	for _, w := range windows {
+		if w.Covers(shift) {
+			return nil
+		}
+	}
+	return fmt.Errorf("outside availability: %w", ErrWorkerUnavailable)
+}
+
+func (w Work) checkAvailability(ctx context.Context, repo Repository, shift Shift) error {
+	windows, err := repo.Availabilities(ctx, AvailabilityFilter{WorkerID: shift.WorkerID})
+	if err != nil {
+		return fmt.Errorf("list availabilities: %w", err)
+	}
+	from, to := shift.StartTime(), shift.EndTime()
+	absences, err := repo.Unavailabilities(
+		ctx, UnavailabilityFilter{WorkerID: shift.WorkerID, From: &from, To: &to},
+	)
+	if err != nil {
+		return fmt.Errorf("list unavailabilities: %w", err)
+	}
+	// approved leaves make worker unavailable as well
+	approved := LeaveApproved
+	leaves, err := repo.Leaves(
+		ctx, LeavesFilter{WorkerID: shift.WorkerID, Status: &approved, From: &from, To: &to},
+	)
+	if err != nil {
+		return fmt.Errorf("list leaves: %w", err)
+	}
+	for _, l := range leaves {
+		absences = append(absences, l.Unavailability())
+	}
+	return CheckAvailability(shift, windows, absences)
+}
+
+func (w Work) CreateAvailability(ctx context.Context, availability Availability) (Availability, error) {
+	availability.ID = w.uuid()
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		if _, err := repo.Worker(ctx, availability.WorkerID); err != nil {
+			return fmt.Errorf("get worker: %w", err)
+		}
+		if err := repo.CreateAvailability(ctx, availability); err != nil {
+			return fmt.Errorf("creating availability: %w", err)
+		}
+		return w.audit(ctx, repo, "availability", "create", availability.ID, nil, availability)
+	})
+	if err != nil {
+		return Availability{}, fmt.Errorf("create availability transaction: %w", err)
+	}
+	return availability, nil
+}
+
+func (w Work) Availabilities(ctx context.Context, filter AvailabilityFilter) ([]Availability, error) {
+	availabilities, err := w.repo.Availabilities(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list availabilities: %w", err)
+	}
+	return availabilities, nil
+}
+
+// UpdateAvailability changes window time, worker of the window is kept.
+func (w Work) UpdateAvailability(ctx context.Context, availability Availability) (Availability, error) {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		existing, err := repo.Availability(ctx, availability.ID)
+		if err != nil {
+			return fmt.Errorf("get availability: %w", err)
+		}
+		availability.WorkerID = existing.WorkerID
+		if err := repo.UpdateAvailability(ctx, availability); err != nil {
+			return fmt.Errorf("updating availability: %w", err)
+		}
+		return w.audit(ctx, repo, "availability", "update", availability.ID, existing, availability)
+	})
+	if err != nil {
+		return Availability{}, fmt.Errorf("update availability transaction: %w", err)
+	}
+	return availability, nil
+}
+
+func (w Work) DeleteAvailability(ctx context.Context, id uuid.UUID) error {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		availability, err := repo.Availability(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get availability: %w", err)
+		}
+		if err := repo.DeleteAvailability(ctx, id); err != nil {
+			return fmt.Errorf("deleting availability: %w", err)
+		}
+		return w.audit(ctx, repo, "availability", "delete", id, availability, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("delete availability transaction: %w", err)
+	}
+	return nil
+}
+
+func (w Work) CreateUnavailability(ctx context.Context, unavailability Unavailability) (Unavailability, error) {
+	unavailability.ID = w.uuid()
+	unavailability.normalize()
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		if _, err := repo.Worker(ctx, unavailability.WorkerID); err != nil {
+			return fmt.Errorf("get worker: %w", err)
+		}
+		if err := repo.CreateUnavailability(ctx, unavailability); err != nil {
+			return fmt.Errorf("creating unavailability: %w", err)
+		}
+		return w.audit(ctx, repo, "unavailability", "create", unavailability.ID, nil, unavailability)
+	})
+	if err != nil {
+		return Unavailability{}, fmt.Errorf("create unavailability transaction: %w", err)
+	}
+	return unavailability, nil
+}
+
+func (w Work) Unavailabilities(ctx context.Context, filter UnavailabilityFilter) ([]Unavailability, error) {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, ErrInvalidRange
+	}
+	unavailabilities, err := w.repo.Unavailabilities(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list unavailabilities: %w", err)
+	}
+	return unavailabilities, nil
+}
+
+// UpdateUnavailability changes time range and reason, worker is kept.
+func (w Work) UpdateUnavailability(ctx context.Context, unavailability Unavailability) (Unavailability, error) {
+	unavailability.normalize()
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		existing, err := repo.Unavailability(ctx, unavailability.ID)
+		if err != nil {
+			return fmt.Errorf("get unavailability: %w", err)
+		}
+		unavailability.WorkerID = existing.WorkerID
+		if err := repo.UpdateUnavailability(ctx, unavailability); err != nil {
+			return fmt.Errorf("updating unavailability: %w", err)
+		}
+		return w.audit(ctx, repo, "unavailability", "update", unavailability.ID, existing, unavailability)
+	})
+	if err != nil {
+		return Unavailability{}, fmt.Errorf("update unavailability transaction: %w", err)
+	}
+	return unavailability, nil
+}
+
+func (w Work) DeleteUnavailability(ctx context.Context, id uuid.UUID) error {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		unavailability, err := repo.Unavailability(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get unavailability: %w", err)
+		}
+		if err := repo.DeleteUnavailability(ctx, id); err != nil {
+			return fmt.Errorf("deleting unavailability: %w", err)
+		}
+		return w.audit(ctx, repo, "unavailability", "delete", id, unavailability, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("delete unavailability transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestCheckAvailability(t *testing.T) {
+	t.Parallel()
+	// Monday
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	weekdays := planner.Availability{
+		Weekdays: planner.NewWeekdays(time.Monday, time.Tuesday),
+		Start:    planner.NewClock(8, 0),
+		End:      planner.NewClock(18, 0),
+	}
+	nights := planner.Availability{
+		Weekdays: planner.NewWeekdays(time.Sunday),
+		Start:    planner.NewClock(20, 0),
+		End:      planner.NewClock(6, 0),
+	}
+	sick := planner.Unavailability{From: date.Add(12 * time.Hour), To: date.AddDate(0, 0, 1)}
+
+	tests := []struct {
+		name     string
+		shift    planner.Shift
+		windows  []planner.Availability
+		absences []planner.Unavailability
+		expErr   error
+	}{
+		{
+			name:  "No windows",
+			shift: planner.Shift{Date: date, Start: planner.NewClock(2, 0), End: planner.NewClock(10, 0)},
+		},
+		{
+			name:    "Inside window",
+			shift:   planner.Shift{Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(18, 0)},
+			windows: []planner.Availability{weekdays},
+		},
+		{
+			name:    "Outside window",
+			shift:   planner.Shift{Date: date, Start: planner.NewClock(14, 0), End: planner.NewClock(22, 0)},
+			windows: []planner.Availability{weekdays},
+			expErr:  planner.ErrWorkerUnavailable,
+		},
+		{
+			name:    "Other weekday",
+			shift:   planner.Shift{Date: date.AddDate(0, 0, 2), Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+			windows: []planner.Availability{weekdays},
+			expErr:  planner.ErrWorkerUnavailable,
+		},
+		{
+			name:    "Overnight window of previous day",
+			shift:   planner.Shift{Date: date, Start: planner.NewClock(0, 0), End: planner.NewClock(6, 0)},
+			windows: []planner.Availability{weekdays, nights},
+		},
+		{
+			name:     "Overlaps unavailability",
+			shift:    planner.Shift{Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+			windows:  []planner.Availability{weekdays},
+			absences: []planner.Unavailability{sick},
+			expErr:   planner.ErrWorkerUnavailable,
+		},
+		{
+			name:     "Ends when unavailability starts",
+			shift:    planner.Shift{Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(12, 0)},
+			absences: []planner.Unavailability{sick},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := planner.CheckAvailability(tt.shift, tt.windows, tt.absences)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestCreateShiftUnavailable(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1 := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	from, to := shift.StartTime(), shift.EndTime()
+	leave := planner.Unavailability{WorkerID: id1, From: date, To: date.AddDate(0, 0, 7), Reason: "vacation"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		repo.EXPECT().Availabilities(ctx, planner.AvailabilityFilter{WorkerID: &id1}).Return(nil, nil),
+		repo.EXPECT().Unavailabilities(ctx, planner.UnavailabilityFilter{WorkerID: &id1, From: &from, To: &to}).Return([]planner.Unavailability{leave}, nil),
+		repo.EXPECT().Leaves(ctx, gomock.Any()).Return(nil, nil),
+	)
+
+	plan := planner.New(repo, planner.UUIDGenerator(genID))
+	_, err := plan.CreateShift(ctx, shift)
+	assert.ErrorIs(t, err, planner.ErrWorkerUnavailable)
+}
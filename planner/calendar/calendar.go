@@ -0,0 +1,237 @@
+// Package calendar renders shifts as iCalendar (RFC 5545) feeds.
+package calendar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+// RefreshInterval is suggested to subscribed calendar apps.
+const RefreshInterval = time.Hour
+
+type Filter struct {
+	// WorkerID selects shifts of the worker, all shifts including open ones are selected without it.
+	WorkerID   *uuid.UUID `json:"worker_id"`
+	LocationID *uuid.UUID `json:"location_id"`
+	// From and To are inclusive range of shift dates.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Calendar is a VCALENDAR object with one event per shift.
+type Calendar struct {
+	Name string
+	// Stamp is a time calendar was generated at, it's used as DTSTAMP
+	// of events without change history.
+	Stamp  time.Time
+	Events []Event
+}
+
+type Event struct {
+	// UID is derived from shift ID, so calendar apps update
+	// the same event when the shift changes.
+	UID         string
+	Start, End  time.Time
+	Summary     string
+	Location    string
+	Description string
+	// Modified is a time of the last change of the shift and
+	// Sequence is a number of changes after it was created,
+	// both are taken from the audit log.
+	Modified time.Time
+	Sequence int
+}
+
+type Feeds struct {
+	repo planner.Repository
+}
+
+func New(repo planner.Repository) Feeds {
+	return Feeds{repo: repo}
+}
+
+// Calendar returns shifts matching the filter as calendar events.
+func (f Feeds) Calendar(ctx context.Context, filter Filter) (Calendar, error) {
+	from, to, err := planner.CheckRange(ctx, f.repo, filter.From, filter.To, filter.LocationID)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	cal := Calendar{Name: "Shifts", Stamp: time.Now().UTC()}
+	names := map[uuid.UUID]string{}
+	if filter.WorkerID != nil {
+		worker, err := f.repo.Worker(ctx, *filter.WorkerID)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("get worker: %w", err)
+		}
+		cal.Name = "Shifts of " + worker.Name
+	} else {
+		workers, err := f.repo.Workers(ctx, planner.WorkersFilter{})
+		if err != nil {
+			return Calendar{}, fmt.Errorf("list workers: %w", err)
+		}
+		for _, w := range workers {
+			names[w.ID] = w.Name
+		}
+	}
+	locations, err := f.repo.Locations(ctx)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("list locations: %w", err)
+	}
+	places := map[uuid.UUID]string{}
+	for _, l := range locations {
+		places[l.ID] = l.Name
+	}
+	if filter.LocationID != nil {
+		cal.Name += " at " + places[*filter.LocationID]
+	}
+
+	shifts, err := f.repo.Shifts(ctx, planner.ShiftsFilter{
+		WorkerID: filter.WorkerID, LocationID: filter.LocationID, From: &from, To: &to,
+	})
+	if err != nil {
+		return Calendar{}, fmt.Errorf("list shifts: %w", err)
+	}
+	ids := make([]uuid.UUID, 0, len(shifts))
+	for _, s := range shifts {
+		ids = append(ids, s.ID)
+	}
+	revisions, err := f.repo.Revisions(ctx, "shift", ids)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("list shift revisions: %w", err)
+	}
+	changes := map[uuid.UUID]planner.Revision{}
+	for _, r := range revisions {
+		changes[r.EntityID] = r
+	}
+
+	cal.Events = make([]Event, 0, len(shifts))
+	for _, s := range shifts {
+		event := Event{UID: s.ID.String() + "@wrkpln", Start: s.StartTime(), End: s.EndTime(), Summary: "Shift"}
+		if filter.WorkerID == nil {
+			event.Summary = "Open shift"
+			if !s.Open() {
+				event.Summary = names[*s.WorkerID]
+			}
+		}
+		if s.LocationID != nil {
+			event.Location = places[*s.LocationID]
+		}
+		if len(s.Skills) > 0 {
+			event.Description = "Skills: " + strings.Join(s.Skills, ", ")
+		}
+		if r, ok := changes[s.ID]; ok {
+			event.Modified, event.Sequence = r.Modified, r.Sequence
+		}
+		cal.Events = append(cal.Events, event)
+	}
+	return cal, nil
+}
+
+// WriteTo writes calendar in iCalendar format.
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", "-//wrkpln//shifts//EN")
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	cw.text("X-WR-CALNAME", c.Name)
+	cw.line("REFRESH-INTERVAL;VALUE=DURATION", duration(RefreshInterval))
+	cw.line("X-PUBLISHED-TTL", duration(RefreshInterval))
+	for _, e := range c.Events {
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", e.UID)
+		// stamp is stable while the shift is unchanged, so calendar apps don't see updates on every refresh
+		stamp := c.Stamp
+		if !e.Modified.IsZero() {
+			stamp = e.Modified
+		}
+		cw.line("DTSTAMP", timestamp(stamp))
+		cw.line("DTSTART", timestamp(e.Start))
+		cw.line("DTEND", timestamp(e.End))
+		cw.text("SUMMARY", e.Summary)
+		if e.Location != "" {
+			cw.text("LOCATION", e.Location)
+		}
+		if e.Description != "" {
+			cw.text("DESCRIPTION", e.Description)
+		}
+		if !e.Modified.IsZero() {
+			cw.line("LAST-MODIFIED", timestamp(e.Modified))
+		}
+		cw.line("SEQUENCE", strconv.Itoa(e.Sequence))
+		cw.line("END", "VEVENT")
+	}
+	cw.line("END", "VCALENDAR")
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// contentWriter writes content lines folded to 75 octets
+// and keeps the first error.
+type contentWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+const maxLineOctets = 75
+
+func (cw *contentWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	line := name + ":" + value
+	for folded := false; len(line) > 0; folded = true {
+		limit := maxLineOctets
+		// folded lines start with a space
+		if folded {
+			cw.write(" ")
+			limit--
+		}
+		cut := min(len(line), limit)
+		// multi-byte characters aren't split
+		for cut < len(line) && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		cw.write(line[:cut])
+		cw.write("\r\n")
+		line = line[cut:]
+	}
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// text writes property of TEXT value type.
+func (cw *contentWriter) text(name, value string) {
+	cw.line(name, textEscaper.Replace(value))
+}
+
+func (cw *contentWriter) write(s string) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.w.WriteString(s)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func duration(d time.Duration) string {
+	return fmt.Sprintf("PT%dM", int(d/time.Minute))
+}
@@ -0,0 +1,197 @@
+package calendar_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/calendar"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestCalendar(t *testing.T) {
+	t.Parallel()
+	w1, w2 := uuid.New(), uuid.New()
+	location := planner.Location{ID: uuid.New(), Name: "North"}
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	to := date.AddDate(0, 0, 6)
+	day := planner.Shift{
+		ID: uuid.New(), WorkerID: &w1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0),
+		LocationID: &location.ID, Skills: []string{"cashier", "forklift"},
+	}
+	night := planner.Shift{ID: uuid.New(), WorkerID: &w2, Date: date, Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)}
+	open := planner.Shift{ID: uuid.New(), Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(12, 0)}
+	created := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+	updated := created.Add(48 * time.Hour)
+	// open shift has no audit entries
+	revisions := []planner.Revision{
+		{EntityID: day.ID, Modified: updated, Sequence: 2},
+		{EntityID: night.ID, Modified: created},
+	}
+
+	tests := []struct {
+		name   string
+		filter calendar.Filter
+		expect func(ctx context.Context, repo *repomock.MockRepository)
+		want   calendar.Calendar
+		expErr error
+	}{
+		{
+			name:   "All workers",
+			filter: calendar.Filter{From: date.Add(time.Hour), To: to},
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				repo.EXPECT().Workers(ctx, planner.WorkersFilter{}).Return([]planner.Worker{{ID: w1, Name: "Ann"}, {ID: w2, Name: "Bob"}}, nil)
+				repo.EXPECT().Locations(ctx).Return([]planner.Location{location}, nil)
+				repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{From: &date, To: &to}).Return([]planner.Shift{day, night, open}, nil)
+				repo.EXPECT().Revisions(ctx, "shift", []uuid.UUID{day.ID, night.ID, open.ID}).Return(revisions, nil)
+			},
+			want: calendar.Calendar{Name: "Shifts", Events: []calendar.Event{
+				{
+					UID: day.ID.String() + "@wrkpln", Start: date.Add(8 * time.Hour), End: date.Add(16 * time.Hour),
+					Summary: "Ann", Location: "North", Description: "Skills: cashier, forklift", Modified: updated, Sequence: 2,
+				},
+				{
+					UID: night.ID.String() + "@wrkpln", Start: date.Add(22 * time.Hour), End: date.Add(30 * time.Hour),
+					Summary: "Bob", Modified: created,
+				},
+				{UID: open.ID.String() + "@wrkpln", Start: date.Add(8 * time.Hour), End: date.Add(12 * time.Hour), Summary: "Open shift"},
+			}},
+		},
+		{
+			name:   "Worker at location",
+			filter: calendar.Filter{WorkerID: &w1, LocationID: &location.ID, From: date, To: to},
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				repo.EXPECT().Location(ctx, location.ID).Return(location, nil)
+				repo.EXPECT().Worker(ctx, w1).Return(planner.Worker{ID: w1, Name: "Ann"}, nil)
+				repo.EXPECT().Locations(ctx).Return([]planner.Location{location}, nil)
+				repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &w1, LocationID: &location.ID, From: &date, To: &to}).
+					Return([]planner.Shift{day}, nil)
+				repo.EXPECT().Revisions(ctx, "shift", []uuid.UUID{day.ID}).Return(revisions[:1], nil)
+			},
+			want: calendar.Calendar{Name: "Shifts of Ann at North", Events: []calendar.Event{{
+				UID: day.ID.String() + "@wrkpln", Start: date.Add(8 * time.Hour), End: date.Add(16 * time.Hour),
+				Summary: "Shift", Location: "North", Description: "Skills: cashier, forklift", Modified: updated, Sequence: 2,
+			}}},
+		},
+		{
+			name:   "Revisions error",
+			filter: calendar.Filter{From: date, To: to},
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				repo.EXPECT().Workers(ctx, planner.WorkersFilter{}).Return(nil, nil)
+				repo.EXPECT().Locations(ctx).Return(nil, nil)
+				repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{From: &date, To: &to}).Return([]planner.Shift{open}, nil)
+				repo.EXPECT().Revisions(ctx, "shift", []uuid.UUID{open.ID}).Return(nil, planner.ErrNoRecord)
+			},
+			expErr: planner.ErrNoRecord,
+		},
+		{
+			name:   "Unknown worker",
+			filter: calendar.Filter{WorkerID: &w1, From: date, To: to},
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				repo.EXPECT().Worker(ctx, w1).Return(planner.Worker{}, planner.ErrNoRecord)
+			},
+			expErr: planner.ErrNoRecord,
+		},
+		{
+			name:   "Unknown location",
+			filter: calendar.Filter{LocationID: &w1, From: date, To: to},
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				repo.EXPECT().Location(ctx, w1).Return(planner.Location{}, planner.ErrNoRecord)
+			},
+			expErr: planner.ErrNoRecord,
+		},
+		{
+			name:   "Invalid range",
+			filter: calendar.Filter{From: to, To: date},
+			expErr: planner.ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+			if tt.expect != nil {
+				tt.expect(ctx, repo)
+			}
+
+			cal, err := calendar.New(repo).Calendar(ctx, tt.filter)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now(), cal.Stamp, time.Minute)
+			cal.Stamp = time.Time{}
+			assert.Equal(t, tt.want, cal)
+		})
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, 11, 3, 22, 0, 0, 0, time.UTC)
+	cal := calendar.Calendar{
+		Name:  "Shifts of Ann, Bob; Eve",
+		Stamp: time.Date(2025, 11, 1, 12, 30, 5, 0, time.UTC),
+		Events: []calendar.Event{
+			{
+				UID: "a@wrkpln", Start: start, End: start.Add(8 * time.Hour), Summary: "Shift", Location: `North\South`,
+				Modified: time.Date(2025, 10, 30, 8, 15, 0, 0, time.UTC), Sequence: 3,
+			},
+			{
+				UID: "b@wrkpln", Start: start, End: start.Add(time.Hour), Summary: "Open shift",
+				Description: "Skills: " + strings.Repeat("ø", 40) + "\nbring badge",
+			},
+		},
+	}
+
+	var b strings.Builder
+	n, err := cal.WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(b.Len()), n)
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//wrkpln//shifts//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`X-WR-CALNAME:Shifts of Ann\, Bob\; Eve`,
+		"REFRESH-INTERVAL;VALUE=DURATION:PT60M",
+		"X-PUBLISHED-TTL:PT60M",
+		"BEGIN:VEVENT",
+		"UID:a@wrkpln",
+		// stamp of changed event is the time of the last change
+		"DTSTAMP:20251030T081500Z",
+		"DTSTART:20251103T220000Z",
+		"DTEND:20251104T060000Z",
+		"SUMMARY:Shift",
+		`LOCATION:North\\South`,
+		"LAST-MODIFIED:20251030T081500Z",
+		"SEQUENCE:3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:b@wrkpln",
+		"DTSTAMP:20251101T123005Z",
+		"DTSTART:20251103T220000Z",
+		"DTEND:20251103T230000Z",
+		"SUMMARY:Open shift",
+		// lines are folded at 75 octets without splitting characters
+		"DESCRIPTION:Skills: " + strings.Repeat("ø", 27),
+		" " + strings.Repeat("ø", 13) + `\nbring badge`,
+		"SEQUENCE:0",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), b.String())
+}
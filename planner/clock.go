@@ -0,0 +1,63 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Clock is a time of day in minutes since midnight,
+// 24:00 is allowed to mark the end of the day.
+type Clock int
+
+const (
+	MinutesInHour = 60
+	MinutesInDay  = 24 * MinutesInHour
+)
+
+func NewClock(hour, minute int) Clock {
+	return Clock(hour*MinutesInHour + minute)
+}
+
+// Hour returns hour of day rounded down.
+func (c Clock) Hour() int {
+	return int(c) / MinutesInHour
+}
+
+// CeilHour returns hour of day rounded up.
+func (c Clock) CeilHour() int {
+	return (int(c) + MinutesInHour - 1) / MinutesInHour
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/MinutesInHour, int(c)%MinutesInHour)
+}
+
+func (c Clock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+func (c *Clock) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("clock: %w", err)
+	}
+	clock, err := ParseClock(s)
+	if err != nil {
+		return err
+	}
+	*c = clock
+	return nil
+}
+
+// ParseClock parses time of day in HH:MM format.
+func ParseClock(s string) (Clock, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(s, "%2d:%2d", &hour, &minute); err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("clock %q: expected HH:MM", s)
+	}
+	clock := NewClock(hour, minute)
+	if hour < 0 || minute < 0 || minute >= MinutesInHour || clock > MinutesInDay {
+		return 0, fmt.Errorf("clock %q: out of range", s)
+	}
+	return clock, nil
+}
@@ -0,0 +1,91 @@
+package planner_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+func TestParseClock(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input  string
+		want   planner.Clock
+		hasErr bool
+	}{
+		{input: "00:00", want: 0},
+		{input: "08:30", want: 8*60 + 30},
+		{input: "24:00", want: 24 * 60},
+		{input: "24:01", hasErr: true},
+		{input: "12:60", hasErr: true},
+		{input: "8:30", hasErr: true},
+		{input: "08-30", hasErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+			result, err := planner.ParseClock(tt.input)
+			if tt.hasErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			assert.Equal(t, tt.input, result.String())
+		})
+	}
+}
+
+func TestShiftJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input string
+		want  planner.Shift
+	}{
+		{
+			name:  "Minutes",
+			input: `{"start": "08:30", "end": "17:15"}`,
+			want:  planner.Shift{Start: planner.NewClock(8, 30), End: planner.NewClock(17, 15)},
+		},
+		{
+			name:  "Hours",
+			input: `{"start_hour": 0, "end_hour": 24}`,
+			want:  planner.Shift{Start: planner.NewClock(0, 0), End: planner.NewClock(24, 0)},
+		},
+		{
+			name:  "Open shift",
+			input: `{"worker_id": null, "start": "08:00", "end": "16:00"}`,
+			want:  planner.Shift{Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+		},
+		{
+			name:  "Minutes take precedence",
+			input: `{"start": "08:30", "start_hour": 7, "end_hour": 16}`,
+			want:  planner.Shift{Start: planner.NewClock(8, 30), End: planner.NewClock(16, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := planner.Shift{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.input), &result))
+			assert.Equal(t, tt.want, result)
+		})
+	}
+
+	data, err := json.Marshal(planner.Shift{Start: planner.NewClock(8, 30), End: planner.NewClock(17, 15)})
+	assert.NoError(t, err)
+	out := map[string]any{}
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, "08:30", out["start"])
+	assert.Equal(t, "17:15", out["end"])
+	assert.Equal(t, float64(8), out["start_hour"])
+	assert.Equal(t, float64(18), out["end_hour"])
+	assert.Nil(t, out["worker_id"])
+	assert.Contains(t, out, "worker_id")
+}
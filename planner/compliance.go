@@ -0,0 +1,450 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const day = 24 * time.Hour
+
+// Rule is a labour-law rule checked against the schedule of a worker.
+type Rule interface {
+	// Name identifies the rule in violations and configuration.
+	Name() string
+	// Span is the longest period the rule looks at, it limits
+	// the range of shifts loaded around the checked one.
+	Span() time.Duration
+	// Check returns violations of the rule by shifts of one worker,
+	// shifts are sorted by start time.
+	Check(shifts []Shift) []Violation
+}
+
+// ComplianceRule is a rule with its enforcement.
+type ComplianceRule struct {
+	Rule
+	// Warn makes violations of the rule warnings instead of blocking the change.
+	Warn bool
+}
+
+// Violation is a period of worker's schedule breaking the rule.
+type Violation struct {
+	Rule     string    `json:"rule"`
+	WorkerID uuid.UUID `json:"worker_id"`
+	// From and To are bounds of the period where the rule is broken.
+	From     time.Time   `json:"from"`
+	To       time.Time   `json:"to"`
+	Detail   string      `json:"detail"`
+	ShiftIDs []uuid.UUID `json:"shift_ids"`
+	// Blocking is set for violations preventing the change, others are warnings.
+	Blocking bool `json:"blocking"`
+}
+
+// ComplianceError lists blocking violations caused by the change.
+type ComplianceError struct {
+	Violations []Violation
+}
+
+func (e ComplianceError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if !slices.Contains(rules, v.Rule) {
+			rules = append(rules, v.Rule)
+		}
+	}
+	return fmt.Sprintf("%s: %s", ErrNonCompliant, strings.Join(rules, ", "))
+}
+
+func (e ComplianceError) Unwrap() error {
+	return ErrNonCompliant
+}
+
+type ComplianceFilter struct {
+	WorkerID *uuid.UUID `json:"worker_id"`
+	// From and To are inclusive range of dates to check.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// MaxWeeklyHours limits working time of a calendar week starting on Monday,
+// shifts count to the week of their date.
+type MaxWeeklyHours struct {
+	Max time.Duration
+}
+
+func (MaxWeeklyHours) Name() string {
+	return "max_weekly_hours"
+}
+
+func (MaxWeeklyHours) Span() time.Duration {
+	return 7 * day
+}
+
+func (r MaxWeeklyHours) Check(shifts []Shift) []Violation {
+	weeks := map[time.Time][]Shift{}
+	var starts []time.Time
+	for _, s := range shifts {
+		monday := WeekStart(s.Date)
+		if _, ok := weeks[monday]; !ok {
+			starts = append(starts, monday)
+		}
+		weeks[monday] = append(weeks[monday], s)
+	}
+	var violations []Violation
+	for _, monday := range starts {
+		var total time.Duration
+		for _, s := range weeks[monday] {
+			total += s.Duration()
+		}
+		if total <= r.Max {
+			continue
+		}
+		violations = append(violations, Violation{
+			From: monday, To: monday.AddDate(0, 0, 7),
+			Detail:   fmt.Sprintf("%g hours scheduled, at most %g allowed", total.Hours(), r.Max.Hours()),
+			ShiftIDs: shiftIDs(weeks[monday]),
+		})
+	}
+	return violations
+}
+
+// MaxConsecutiveDays limits the number of days in a row with shifts.
+type MaxConsecutiveDays struct {
+	Days int
+}
+
+func (MaxConsecutiveDays) Name() string {
+	return "max_consecutive_days"
+}
+
+func (r MaxConsecutiveDays) Span() time.Duration {
+	return time.Duration(r.Days) * day
+}
+
+func (r MaxConsecutiveDays) Check(shifts []Shift) []Violation {
+	var violations []Violation
+	for _, run := range dateRuns(shifts) {
+		days := int(run.to.Sub(run.from)/day) + 1
+		if days <= r.Days {
+			continue
+		}
+		violations = append(violations, Violation{
+			From: run.from, To: run.to.AddDate(0, 0, 1),
+			Detail:   fmt.Sprintf("%d days in a row, at most %d allowed", days, r.Days),
+			ShiftIDs: shiftIDs(run.shifts),
+		})
+	}
+	return violations
+}
+
+// MinRestBetween requires at least Rest time between consecutive shifts.
+type MinRestBetween struct {
+	Rest time.Duration
+}
+
+func (MinRestBetween) Name() string {
+	return "min_rest"
+}
+
+func (r MinRestBetween) Span() time.Duration {
+	return r.Rest
+}
+
+func (r MinRestBetween) Check(shifts []Shift) []Violation {
+	var violations []Violation
+	for i := 1; i < len(shifts); i++ {
+		prev, next := shifts[i-1], shifts[i]
+		rest := next.StartTime().Sub(prev.EndTime())
+		if rest >= r.Rest {
+			continue
+		}
+		from, to := prev.EndTime(), next.StartTime()
+		// overlapping shifts have no rest at all
+		if rest < 0 {
+			from, to = to, from
+			rest = 0
+		}
+		violations = append(violations, Violation{
+			From: from, To: to,
+			Detail:   fmt.Sprintf("%s of rest, at least %s required", rest, r.Rest),
+			ShiftIDs: []uuid.UUID{prev.ID, next.ID},
+		})
+	}
+	return violations
+}
+
+// MinDaysOff requires at least Days days without shifts in any Period days in a row.
+type MinDaysOff struct {
+	Days   int
+	Period int
+}
+
+func (MinDaysOff) Name() string {
+	return "min_days_off"
+}
+
+func (r MinDaysOff) Span() time.Duration {
+	return time.Duration(r.Period) * day
+}
+
+func (r MinDaysOff) Check(shifts []Shift) []Violation {
+	if len(shifts) == 0 {
+		return nil
+	}
+	worked := map[time.Time]bool{}
+	first, last := shifts[0].Date, shifts[0].Date
+	for _, s := range shifts {
+		worked[s.Date] = true
+		if s.Date.Before(first) {
+			first = s.Date
+		}
+		if s.Date.After(last) {
+			last = s.Date
+		}
+	}
+	// violating windows in a row are reported as one violation
+	var violations []Violation
+	var current *Violation
+	for start := first.AddDate(0, 0, 1-r.Period); !start.After(last); start = start.AddDate(0, 0, 1) {
+		end := start.AddDate(0, 0, r.Period)
+		off := 0
+		for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+			if !worked[date] {
+				off++
+			}
+		}
+		if off >= r.Days {
+			current = nil
+			continue
+		}
+		if current != nil && !start.After(current.To) {
+			current.To = end
+			continue
+		}
+		violations = append(violations, Violation{From: start, To: end})
+		current = &violations[len(violations)-1]
+	}
+	for i, v := range violations {
+		var inside []Shift
+		for _, s := range shifts {
+			if !s.Date.Before(v.From) && s.Date.Before(v.To) {
+				inside = append(inside, s)
+			}
+		}
+		violations[i].ShiftIDs = shiftIDs(inside)
+		violations[i].Detail = fmt.Sprintf("less than %d days off in %d days", r.Days, r.Period)
+	}
+	return violations
+}
+
+// ParseRules parses "name:limit[:warn]" rule specs:
+// max_weekly_hours:48h, max_consecutive_days:6, min_rest:11h, min_days_off:2/7.
+// Violations of rules with warn mode don't block changes.
+func ParseRules(specs []string) ([]ComplianceRule, error) {
+	rules := make([]ComplianceRule, 0, len(specs))
+	for _, spec := range specs {
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("rule %q: expected name:limit[:mode]", spec)
+		}
+		rule := ComplianceRule{}
+		if len(parts) == 3 {
+			switch parts[2] {
+			case "block":
+			case "warn":
+				rule.Warn = true
+			default:
+				return nil, fmt.Errorf("rule %q: unknown mode %q", spec, parts[2])
+			}
+		}
+		var err error
+		rule.Rule, err = parseRule(parts[0], parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", spec, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseRule(name, limit string) (Rule, error) {
+	switch name {
+	case MaxWeeklyHours{}.Name():
+		max, err := time.ParseDuration(limit)
+		if err != nil || max <= 0 {
+			return nil, fmt.Errorf("limit must be positive duration")
+		}
+		return MaxWeeklyHours{Max: max}, nil
+	case MaxConsecutiveDays{}.Name():
+		days, err := strconv.Atoi(limit)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("limit must be positive integer")
+		}
+		return MaxConsecutiveDays{Days: days}, nil
+	case MinRestBetween{}.Name():
+		rest, err := time.ParseDuration(limit)
+		if err != nil || rest <= 0 {
+			return nil, fmt.Errorf("limit must be positive duration")
+		}
+		return MinRestBetween{Rest: rest}, nil
+	case MinDaysOff{}.Name():
+		daysStr, periodStr, _ := strings.Cut(limit, "/")
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("days off must be positive integer")
+		}
+		period, err := strconv.Atoi(periodStr)
+		if err != nil || period <= days {
+			return nil, fmt.Errorf("period must be integer greater than days off")
+		}
+		return MinDaysOff{Days: days, Period: period}, nil
+	default:
+		return nil, fmt.Errorf("unknown rule %q", name)
+	}
+}
+
+// Evaluate checks shifts of one worker against rules.
+func Evaluate(rules []ComplianceRule, shifts []Shift) []Violation {
+	if len(shifts) == 0 {
+		return nil
+	}
+	shifts = slices.Clone(shifts)
+	sort.SliceStable(shifts, func(i, j int) bool {
+		return shifts[i].StartTime().Before(shifts[j].StartTime())
+	})
+	var violations []Violation
+	for _, rule := range rules {
+		for _, v := range rule.Check(shifts) {
+			v.Rule = rule.Name()
+			if shifts[0].WorkerID != nil {
+				v.WorkerID = *shifts[0].WorkerID
+			}
+			v.Blocking = !rule.Warn
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// CheckCompliance returns violations of worker schedules overlapping with the range,
+// shifts outside of the range are taken into account as well.
+func (w Work) CheckCompliance(ctx context.Context, filter ComplianceFilter) ([]Violation, error) {
+	from, to, err := CheckRange(ctx, w.repo, filter.From, filter.To, nil)
+	if err != nil {
+		return nil, err
+	}
+	violations := []Violation{}
+	if len(w.rules) == 0 {
+		return violations, nil
+	}
+	days := ComplianceDays(w.rules)
+	loadFrom, loadTo := from.AddDate(0, 0, -days), to.AddDate(0, 0, days)
+	shifts, err := w.repo.Shifts(ctx, ShiftsFilter{WorkerID: filter.WorkerID, From: &loadFrom, To: &loadTo})
+	if err != nil {
+		return nil, fmt.Errorf("list shifts: %w", err)
+	}
+	workers := map[uuid.UUID][]Shift{}
+	var ids []uuid.UUID
+	for _, s := range shifts {
+		if s.Open() {
+			continue
+		}
+		if _, ok := workers[*s.WorkerID]; !ok {
+			ids = append(ids, *s.WorkerID)
+		}
+		workers[*s.WorkerID] = append(workers[*s.WorkerID], s)
+	}
+	end := to.AddDate(0, 0, 1)
+	for _, id := range ids {
+		for _, v := range Evaluate(w.rules, workers[id]) {
+			if v.From.Before(end) && from.Before(v.To) {
+				violations = append(violations, v)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// checkCompliance evaluates rules for worker schedule with the shift booked
+// and returns warnings caused by it, blocking violations are returned as ComplianceError.
+func (w Work) checkCompliance(ctx context.Context, repo Repository, shift Shift) ([]Violation, error) {
+	if len(w.rules) == 0 || shift.Open() {
+		return nil, nil
+	}
+	days := ComplianceDays(w.rules)
+	from, to := shift.Date.AddDate(0, 0, -days), shift.Date.AddDate(0, 0, days)
+	shifts, err := repo.Shifts(ctx, ShiftsFilter{WorkerID: shift.WorkerID, From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("list shifts: %w", err)
+	}
+	return CheckShiftCompliance(w.rules, shifts, shift)
+}
+
+// CheckShiftCompliance evaluates rules for worker schedule of shifts with the shift booked
+// and returns warnings caused by it, blocking violations are returned as ComplianceError.
+// Stored version of the shift among shifts is replaced with it.
+// Violations not involving the shift are ignored, so they don't block unrelated changes.
+func CheckShiftCompliance(rules []ComplianceRule, shifts []Shift, shift Shift) ([]Violation, error) {
+	shifts = slices.DeleteFunc(slices.Clone(shifts), func(s Shift) bool { return s.ID == shift.ID })
+	shifts = append(shifts, shift)
+
+	var warnings, blocking []Violation
+	for _, v := range Evaluate(rules, shifts) {
+		switch {
+		case !slices.Contains(v.ShiftIDs, shift.ID):
+		case v.Blocking:
+			blocking = append(blocking, v)
+		default:
+			warnings = append(warnings, v)
+		}
+	}
+	if len(blocking) > 0 {
+		return nil, ComplianceError{Violations: blocking}
+	}
+	return warnings, nil
+}
+
+// ComplianceDays returns the number of days before and after shift date
+// which affect compliance of the shift.
+func ComplianceDays(rules []ComplianceRule) int {
+	var span time.Duration
+	for _, r := range rules {
+		span = max(span, r.Span())
+	}
+	return 1 + int((span+day-1)/day)
+}
+
+type dateRun struct {
+	from, to time.Time
+	shifts   []Shift
+}
+
+// dateRuns groups shifts sorted by start into runs of consecutive dates.
+func dateRuns(shifts []Shift) []dateRun {
+	var runs []dateRun
+	for _, s := range shifts {
+		if n := len(runs); n > 0 && !s.Date.After(runs[n-1].to.AddDate(0, 0, 1)) {
+			if s.Date.After(runs[n-1].to) {
+				runs[n-1].to = s.Date
+			}
+			runs[n-1].shifts = append(runs[n-1].shifts, s)
+			continue
+		}
+		runs = append(runs, dateRun{from: s.Date, to: s.Date, shifts: []Shift{s}})
+	}
+	return runs
+}
+
+func shiftIDs(shifts []Shift) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(shifts))
+	for _, s := range shifts {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
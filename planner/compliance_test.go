@@ -0,0 +1,232 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestComplianceRules(t *testing.T) {
+	t.Parallel()
+	workerID := uuid.New()
+	// Monday
+	monday := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := func(day, start, end int) planner.Shift {
+		return planner.Shift{
+			ID: uuid.New(), WorkerID: &workerID, Date: monday.AddDate(0, 0, day),
+			Start: planner.NewClock(start, 0), End: planner.NewClock(end, 0),
+		}
+	}
+	week := []planner.Shift{shift(0, 8, 18), shift(1, 8, 18), shift(2, 8, 18), shift(3, 8, 18), shift(4, 8, 18)}
+	nextWeek := shift(7, 8, 18)
+	saturday := shift(5, 8, 12)
+	late, early := shift(8, 14, 23), shift(9, 6, 14)
+
+	tests := []struct {
+		name   string
+		rule   planner.Rule
+		shifts []planner.Shift
+		want   []planner.Violation
+	}{
+		{
+			name:   "Weekly hours within limit",
+			rule:   planner.MaxWeeklyHours{Max: 50 * time.Hour},
+			shifts: append(week, nextWeek),
+		},
+		{
+			name:   "Weekly hours exceeded",
+			rule:   planner.MaxWeeklyHours{Max: 48 * time.Hour},
+			shifts: append(week, nextWeek),
+			want: []planner.Violation{{
+				From: monday, To: monday.AddDate(0, 0, 7),
+				Detail: "50 hours scheduled, at most 48 allowed", ShiftIDs: ids(week...),
+			}},
+		},
+		{
+			name:   "Consecutive days exceeded",
+			rule:   planner.MaxConsecutiveDays{Days: 4},
+			shifts: append(week, nextWeek),
+			want: []planner.Violation{{
+				From: monday, To: monday.AddDate(0, 0, 5),
+				Detail: "5 days in a row, at most 4 allowed", ShiftIDs: ids(week...),
+			}},
+		},
+		{
+			name:   "Rest too short",
+			rule:   planner.MinRestBetween{Rest: 11 * time.Hour},
+			shifts: []planner.Shift{nextWeek, late, early},
+			want: []planner.Violation{{
+				From: late.EndTime(), To: early.StartTime(),
+				Detail: "7h0m0s of rest, at least 11h0m0s required", ShiftIDs: ids(late, early),
+			}},
+		},
+		{
+			name:   "Days off missing",
+			rule:   planner.MinDaysOff{Days: 2, Period: 7},
+			shifts: append(week, saturday, nextWeek),
+			// windows starting from Sunday to Tuesday have one day off only
+			want: []planner.Violation{{
+				From: monday.AddDate(0, 0, -1), To: monday.AddDate(0, 0, 8),
+				Detail: "less than 2 days off in 7 days", ShiftIDs: ids(append(week, saturday, nextWeek)...),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for i := range tt.want {
+				tt.want[i].Rule = tt.rule.Name()
+				tt.want[i].WorkerID = workerID
+				tt.want[i].Blocking = true
+			}
+			result := planner.Evaluate([]planner.ComplianceRule{{Rule: tt.rule}}, tt.shifts)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+	rules, err := planner.ParseRules([]string{
+		"max_weekly_hours:48h", "max_consecutive_days:6:warn", "min_rest:11h:block", "min_days_off:2/7",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []planner.ComplianceRule{
+		{Rule: planner.MaxWeeklyHours{Max: 48 * time.Hour}},
+		{Rule: planner.MaxConsecutiveDays{Days: 6}, Warn: true},
+		{Rule: planner.MinRestBetween{Rest: 11 * time.Hour}},
+		{Rule: planner.MinDaysOff{Days: 2, Period: 7}},
+	}, rules)
+
+	for _, spec := range []string{
+		"max_weekly_hours", "max_weekly_hours:48", "max_consecutive_days:0",
+		"min_days_off:7/7", "min_rest:11h:maybe", "max_sleep:8h",
+	} {
+		_, err := planner.ParseRules([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestCreateShiftCompliance(t *testing.T) {
+	t.Parallel()
+	workerID := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+	// rest rule looks at 2 days around the shift
+	from, to := date.AddDate(0, 0, -2), date.AddDate(0, 0, 2)
+	previous := planner.Shift{ID: uuid.New(), WorkerID: &workerID, Date: prevDay, Start: planner.NewClock(14, 0), End: planner.NewClock(23, 0)}
+	// unrelated violation of earlier shifts doesn't block the change
+	earlier := planner.Shift{ID: uuid.New(), WorkerID: &workerID, Date: prevDay, Start: planner.NewClock(2, 0), End: planner.NewClock(6, 0)}
+	input := planner.Shift{WorkerID: &workerID, Date: date, Start: planner.NewClock(6, 0), End: planner.NewClock(14, 0)}
+
+	tests := []struct {
+		name   string
+		warn   bool
+		expErr error
+	}{
+		{name: "Blocking violation", expErr: planner.ErrNonCompliant},
+		{name: "Warning", warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			rule := planner.ComplianceRule{Rule: planner.MinRestBetween{Rest: 11 * time.Hour}, Warn: tt.warn}
+			plan := planner.New(repo, planner.UUIDGenerator(genID), planner.ConflictRule(planner.NoOverlap{}), planner.Compliance(rule))
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Worker(ctx, workerID).Return(planner.Worker{ID: workerID}, nil),
+				expectAvailable(ctx, repo, workerID),
+				repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &workerID, From: &prevDay, To: &nextDay}).Return([]planner.Shift{earlier, previous}, nil),
+				repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &workerID, From: &from, To: &to}).Return([]planner.Shift{earlier, previous}, nil),
+			}
+			if tt.expErr == nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().CreateShift(ctx, gomock.Any()).Return(nil),
+					expectAudit(ctx, repo, "shift", "create", fixedID),
+				)
+			}
+			gomock.InOrder(expectations...)
+
+			result, err := plan.CreateShift(ctx, input)
+			violation := planner.Violation{
+				Rule: "min_rest", WorkerID: workerID, From: previous.EndTime(), To: date.Add(6 * time.Hour),
+				Detail: "7h0m0s of rest, at least 11h0m0s required", ShiftIDs: []uuid.UUID{previous.ID, fixedID},
+				Blocking: !tt.warn,
+			}
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				var complianceErr planner.ComplianceError
+				assert.ErrorAs(t, err, &complianceErr)
+				assert.Equal(t, []planner.Violation{violation}, complianceErr.Violations)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []planner.Violation{violation}, result.Warnings)
+		})
+	}
+}
+
+func TestCheckCompliance(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	w1, w2 := uuid.New(), uuid.New()
+	monday := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := func(workerID uuid.UUID, day int) planner.Shift {
+		return planner.Shift{
+			ID: uuid.New(), WorkerID: &workerID, Date: monday.AddDate(0, 0, day),
+			Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0),
+		}
+	}
+	// w1 works two days in a row twice, w2 three days before the range
+	shifts := []planner.Shift{
+		shift(w1, 0), shift(w1, 1), shift(w1, 7), shift(w1, 8),
+		shift(w2, -4), shift(w2, -3), shift(w2, -2),
+		{ID: uuid.New(), Date: monday, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+	}
+	// shifts are loaded with margin of the longest rule span
+	from, to := monday.AddDate(0, 0, -2), monday.AddDate(0, 0, 9)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	plan := planner.New(repo, planner.Compliance(planner.ComplianceRule{Rule: planner.MaxConsecutiveDays{Days: 1}, Warn: true}))
+	repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{From: &from, To: &to}).Return(shifts, nil)
+
+	result, err := plan.CheckCompliance(ctx, planner.ComplianceFilter{From: monday.Add(10 * time.Hour), To: monday.AddDate(0, 0, 7)})
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	for _, v := range result {
+		assert.Equal(t, w1, v.WorkerID)
+		assert.False(t, v.Blocking)
+	}
+	assert.Equal(t, monday, result[0].From)
+	assert.Equal(t, monday.AddDate(0, 0, 7), result[1].From)
+
+	_, err = plan.CheckCompliance(ctx, planner.ComplianceFilter{From: monday, To: monday.AddDate(0, 0, -1)})
+	assert.ErrorIs(t, err, planner.ErrInvalidRange)
+}
+
+func ids(shifts ...planner.Shift) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(shifts))
+	for _, s := range shifts {
+		result = append(result, s.ID)
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package planner
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConflictPolicy decides whether two shifts of the same worker can be both booked.
+type ConflictPolicy interface {
+	// Margin is the longest time between two shifts that still can conflict,
+	// it limits the range of shifts to be checked.
+	Margin() time.Duration
+	// Conflict returns the reason why shifts can't be both booked or nil.
+	Conflict(shift, other Shift) error
+}
+
+// ConflictDays returns the number of days before and after shift date
+// which can have shifts conflicting with it. Overnight shifts
+// of neighbouring days are always taken into account.
+func ConflictDays(policy ConflictPolicy) int {
+	day := 24 * time.Hour
+	return 1 + int((policy.Margin()+day-1)/day)
+}
+
+// OnePerDay allows one shift per day for worker,
+// overnight shifts can't overlap with shifts of the next day.
+type OnePerDay struct{}
+
+func (OnePerDay) Margin() time.Duration {
+	return 0
+}
+
+func (OnePerDay) Conflict(shift, other Shift) error {
+	if shift.Date.Equal(other.Date) {
+		return ErrDayAlreadyBooked
+	}
+	if shift.Overlaps(other) {
+		return ErrShiftsOverlap
+	}
+	return nil
+}
+
+// NoOverlap allows any number of shifts per day unless they overlap in time.
+type NoOverlap struct{}
+
+func (NoOverlap) Margin() time.Duration {
+	return 0
+}
+
+func (NoOverlap) Conflict(shift, other Shift) error {
+	if shift.Overlaps(other) {
+		return ErrShiftsOverlap
+	}
+	return nil
+}
+
+// MinRest requires at least Rest time between shifts.
+type MinRest struct {
+	Rest time.Duration
+}
+
+func (r MinRest) Margin() time.Duration {
+	return r.Rest
+}
+
+func (r MinRest) Conflict(shift, other Shift) error {
+	if shift.Overlaps(other) {
+		return ErrShiftsOverlap
+	}
+	if shift.StartTime().Sub(other.EndTime()) < r.Rest &&
+		other.StartTime().Sub(shift.EndTime()) < r.Rest {
+		return ErrRestTooShort
+	}
+	return nil
+}
+
+// ConflictError names the shift which conflicts with the one being booked.
+type ConflictError struct {
+	Shift  Shift
+	Reason Error
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("%s: shift %s", e.Reason, e.Shift.ID)
+}
+
+func (e ConflictError) Unwrap() error {
+	return e.Reason
+}
+
+// BatchError lists rejected items of a batch.
+type BatchError struct {
+	Items []ItemError
+}
+
+// ItemError is an error of a batch item with its index in the batch.
+type ItemError struct {
+	Index int
+	Err   error
+	// ConflictIndex is set when item conflicts with another item of the batch.
+	ConflictIndex *int
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("batch rejected: %d items failed", len(e.Items))
+}
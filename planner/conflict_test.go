@@ -0,0 +1,125 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestConflictPolicies(t *testing.T) {
+	t.Parallel()
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	shift := func(date time.Time, start, end int) planner.Shift {
+		return planner.Shift{Date: date, Start: planner.NewClock(start, 0), End: planner.NewClock(end, 0)}
+	}
+
+	tests := []struct {
+		name   string
+		policy planner.ConflictPolicy
+		shift  planner.Shift
+		other  planner.Shift
+		expErr error
+	}{
+		{
+			name:   "One per day: same day",
+			policy: planner.OnePerDay{},
+			shift:  shift(day, 8, 12),
+			other:  shift(day, 14, 18),
+			expErr: planner.ErrDayAlreadyBooked,
+		},
+		{
+			name:   "One per day: overnight overlap",
+			policy: planner.OnePerDay{},
+			shift:  shift(day, 22, 6),
+			other:  shift(nextDay, 5, 12),
+			expErr: planner.ErrShiftsOverlap,
+		},
+		{
+			name:   "One per day: next day",
+			policy: planner.OnePerDay{},
+			shift:  shift(day, 22, 6),
+			other:  shift(nextDay, 6, 12),
+		},
+		{
+			name:   "No overlap: same day",
+			policy: planner.NoOverlap{},
+			shift:  shift(day, 8, 12),
+			other:  shift(day, 12, 18),
+		},
+		{
+			name:   "No overlap: overlap",
+			policy: planner.NoOverlap{},
+			shift:  shift(day, 8, 13),
+			other:  shift(day, 12, 18),
+			expErr: planner.ErrShiftsOverlap,
+		},
+		{
+			name:   "Min rest: too short after",
+			policy: planner.MinRest{Rest: 11 * time.Hour},
+			shift:  shift(nextDay, 6, 14),
+			other:  shift(day, 14, 22),
+			expErr: planner.ErrRestTooShort,
+		},
+		{
+			name:   "Min rest: too short before",
+			policy: planner.MinRest{Rest: 11 * time.Hour},
+			shift:  shift(day, 14, 22),
+			other:  shift(nextDay, 6, 14),
+			expErr: planner.ErrRestTooShort,
+		},
+		{
+			name:   "Min rest: enough",
+			policy: planner.MinRest{Rest: 11 * time.Hour},
+			shift:  shift(nextDay, 9, 17),
+			other:  shift(day, 14, 22),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.policy.Conflict(tt.shift, tt.other)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestCreateShiftMinRest(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1 := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	// 30 hours of rest require two extra days to be checked
+	from, to := date.AddDate(0, 0, -3), date.AddDate(0, 0, 3)
+	other := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: date.AddDate(0, 0, -1), Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	plan := planner.New(repo, planner.UUIDGenerator(genID), planner.ConflictRule(planner.MinRest{Rest: 30 * time.Hour}))
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		expectAvailable(ctx, repo, id1),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &from, To: &to}).Return([]planner.Shift{other}, nil),
+	)
+
+	_, err := plan.CreateShift(ctx, planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)})
+	assert.ErrorIs(t, err, planner.ErrRestTooShort)
+	var conflictErr planner.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, other, conflictErr.Shift)
+}
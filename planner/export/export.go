@@ -0,0 +1,362 @@
+// Package export renders rosters as grids of workers and days
+// for printing and spreadsheets.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	HTML Format = "html"
+)
+
+var contentTypes = map[Format]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	HTML: "text/html; charset=utf-8",
+}
+
+// ContentType returns MIME type of the format, it's empty for unknown formats.
+func (f Format) ContentType() string {
+	return contentTypes[f]
+}
+
+// dayFormat is used for column headers, weekday helps reading printed rosters.
+const dayFormat = "Mon 2006-01-02"
+
+type Filter struct {
+	LocationID *uuid.UUID `json:"location_id"`
+	// From and To are inclusive range of shift dates.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Roster is a grid of shifts with a row per worker and a column per day.
+type Roster struct {
+	Title string      `json:"title"`
+	Days  []time.Time `json:"days"`
+	// Rows are sorted by worker name, workers without shifts are skipped.
+	// Open shifts are in the last row with nil worker ID.
+	Rows []Row `json:"rows"`
+}
+
+type Row struct {
+	WorkerID uuid.UUID `json:"worker_id"`
+	Name     string    `json:"name"`
+	// Cells has entries of every day of the roster sorted by start.
+	Cells [][]Entry `json:"cells"`
+}
+
+type Entry struct {
+	ShiftID  uuid.UUID     `json:"shift_id"`
+	Start    planner.Clock `json:"start"`
+	End      planner.Clock `json:"end"`
+	Location string        `json:"location,omitempty"`
+}
+
+func (e Entry) String() string {
+	s := e.Start.String() + "-" + e.End.String()
+	if e.Location != "" {
+		s += " " + e.Location
+	}
+	return s
+}
+
+type Exporter struct {
+	plan planner.Work
+}
+
+func New(plan planner.Work) Exporter {
+	return Exporter{plan: plan}
+}
+
+// Roster returns shifts of the date range arranged into a grid.
+func (e Exporter) Roster(ctx context.Context, filter Filter) (Roster, error) {
+	from, to, err := planner.CheckRange(ctx, e.plan, filter.From, filter.To, filter.LocationID)
+	if err != nil {
+		return Roster{}, err
+	}
+
+	locations, err := e.plan.Locations(ctx)
+	if err != nil {
+		return Roster{}, fmt.Errorf("list locations: %w", err)
+	}
+	places := map[uuid.UUID]string{}
+	for _, l := range locations {
+		places[l.ID] = l.Name
+	}
+	roster := Roster{Title: "Roster " + from.Format(time.DateOnly) + " - " + to.Format(time.DateOnly)}
+	if filter.LocationID != nil {
+		roster.Title += " at " + places[*filter.LocationID]
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		roster.Days = append(roster.Days, day)
+	}
+
+	shifts, err := e.shifts(ctx, planner.ShiftsFilter{LocationID: filter.LocationID, From: &from, To: &to})
+	if err != nil {
+		return Roster{}, fmt.Errorf("list shifts: %w", err)
+	}
+	rows := map[uuid.UUID]*Row{}
+	for _, s := range shifts {
+		// open shifts are collected in the row of nil worker ID
+		workerID := uuid.Nil
+		if !s.Open() {
+			workerID = *s.WorkerID
+		}
+		row, ok := rows[workerID]
+		if !ok {
+			row = &Row{WorkerID: workerID, Cells: make([][]Entry, len(roster.Days))}
+			rows[workerID] = row
+		}
+		entry := Entry{ShiftID: s.ID, Start: s.Start, End: s.End}
+		// location is repeated only if roster has shifts of many locations
+		if s.LocationID != nil && filter.LocationID == nil {
+			entry.Location = places[*s.LocationID]
+		}
+		day := int(planner.TruncateDate(s.Date).Sub(from) / (24 * time.Hour))
+		row.Cells[day] = append(row.Cells[day], entry)
+	}
+	for _, row := range rows {
+		for _, cell := range row.Cells {
+			slices.SortFunc(cell, func(a, b Entry) int { return int(a.Start) - int(b.Start) })
+		}
+	}
+
+	workers, err := e.workers(ctx)
+	if err != nil {
+		return Roster{}, fmt.Errorf("list workers: %w", err)
+	}
+	// workers are listed sorted by name
+	for _, w := range workers {
+		if row, ok := rows[w.ID]; ok {
+			row.Name = w.Name
+			roster.Rows = append(roster.Rows, *row)
+		}
+	}
+	if row, ok := rows[uuid.Nil]; ok {
+		row.Name = "Open shifts"
+		roster.Rows = append(roster.Rows, *row)
+	}
+	return roster, nil
+}
+
+// shifts lists all pages of shifts matching the filter.
+func (e Exporter) shifts(ctx context.Context, filter planner.ShiftsFilter) ([]planner.Shift, error) {
+	filter.Limit = planner.MaxLimit
+	var all []planner.Shift
+	for {
+		shifts, next, err := e.plan.Shifts(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, shifts...)
+		if next == nil {
+			return all, nil
+		}
+		filter.After = next
+	}
+}
+
+// workers lists all pages of workers sorted by name.
+func (e Exporter) workers(ctx context.Context) ([]planner.Worker, error) {
+	filter := planner.WorkersFilter{Limit: planner.MaxLimit}
+	var all []planner.Worker
+	for {
+		workers, next, err := e.plan.Workers(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, workers...)
+		if next == nil {
+			return all, nil
+		}
+		filter.After = next
+	}
+}
+
+// Write writes roster in the format.
+func (r Roster) Write(w io.Writer, format Format) error {
+	switch format {
+	case CSV:
+		return r.writeCSV(w)
+	case XLSX:
+		return r.writeXLSX(w)
+	case HTML:
+		return r.writeHTML(w)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// header returns first row of the grid.
+func (r Roster) header() []string {
+	header := make([]string, 0, len(r.Days)+1)
+	header = append(header, "Worker")
+	for _, day := range r.Days {
+		header = append(header, day.Format(dayFormat))
+	}
+	return header
+}
+
+// cells returns text of every cell of the row,
+// entries of a cell are joined with sep.
+func (row Row) cells(sep string) []string {
+	cells := make([]string, 0, len(row.Cells)+1)
+	cells = append(cells, row.Name)
+	for _, cell := range row.Cells {
+		entries := make([]string, 0, len(cell))
+		for _, e := range cell {
+			entries = append(entries, e.String())
+		}
+		cells = append(cells, strings.Join(entries, sep))
+	}
+	return cells
+}
+
+// formulaPrefixes start text which spreadsheet apps evaluate as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// csvCells returns cells of the row like cells does, text which spreadsheet apps
+// opening CSV would evaluate as a formula is prefixed with a quote.
+// XLSX cells are typed as text, so they are never evaluated and aren't escaped.
+func (row Row) csvCells(sep string) []string {
+	cells := row.cells(sep)
+	for i, cell := range cells {
+		if cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0])) {
+			cells[i] = "'" + cell
+		}
+	}
+	return cells
+}
+
+func (r Roster) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.header()); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	for _, row := range r.Rows {
+		if err := cw.Write(row.csvCells("; ")); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	return nil
+}
+
+const sheetName = "Roster"
+
+func (r Roster) writeXLSX(w io.Writer) error {
+	file := excelize.NewFile()
+	defer file.Close()
+	if err := file.SetSheetName(file.GetSheetName(0), sheetName); err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+	// shifts of a day are on separate lines of the cell
+	cellStyle, err := file.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"}})
+	if err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+
+	header := r.header()
+	lastColumn, err := excelize.ColumnNumberToName(len(header))
+	if err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+	err = file.SetSheetRow(sheetName, "A1", &header)
+	if err == nil {
+		err = file.SetCellStyle(sheetName, "A1", lastColumn+"1", headerStyle)
+	}
+	for i, row := range r.Rows {
+		if err != nil {
+			break
+		}
+		line := i + 2
+		cells := row.cells("\n")
+		err = file.SetSheetRow(sheetName, fmt.Sprintf("A%d", line), &cells)
+		if err == nil {
+			err = file.SetCellStyle(sheetName, fmt.Sprintf("B%d", line), fmt.Sprintf("%s%d", lastColumn, line), cellStyle)
+		}
+	}
+	if err == nil {
+		err = file.SetColWidth(sheetName, "A", lastColumn, 18)
+	}
+	if err == nil {
+		// worker names and days stay visible while scrolling
+		err = file.SetPanes(sheetName, &excelize.Panes{
+			Freeze: true, XSplit: 1, YSplit: 1, TopLeftCell: "B2", ActivePane: "bottomRight",
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+	if err := file.Write(w); err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+	return nil
+}
+
+// rosterTemplate is a printable page, it fits a week on landscape A4.
+var rosterTemplate = template.Must(template.New("roster").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page { size: A4 landscape; margin: 1cm; }
+body { font-family: sans-serif; font-size: 11pt; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #444; padding: 4px 6px; vertical-align: top; text-align: left; }
+td { white-space: pre-line; }
+thead { display: table-header-group; }
+tr { page-break-inside: avoid; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range $i, $cell := .}}{{if eq $i 0}}<th>{{$cell}}</th>{{else}}<td>{{$cell}}</td>{{end}}{{end}}</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+func (r Roster) writeHTML(w io.Writer) error {
+	data := struct {
+		Title  string
+		Header []string
+		Rows   [][]string
+	}{Title: r.Title, Header: r.header()}
+	for _, row := range r.Rows {
+		data.Rows = append(data.Rows, row.cells("\n"))
+	}
+	if err := rosterTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("write html: %w", err)
+	}
+	return nil
+}
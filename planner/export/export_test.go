@@ -0,0 +1,173 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/export"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestRoster(t *testing.T) {
+	t.Parallel()
+	w1, w2, w3 := uuid.New(), uuid.New(), uuid.New()
+	location := planner.Location{ID: uuid.New(), Name: "North"}
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	to := date.AddDate(0, 0, 2)
+	shift := func(worker *uuid.UUID, date time.Time, start, end planner.Clock) planner.Shift {
+		return planner.Shift{ID: uuid.New(), WorkerID: worker, Date: date, Start: start, End: end}
+	}
+	late := shift(&w2, date, planner.NewClock(18, 0), planner.NewClock(22, 0))
+	late.LocationID = &location.ID
+	early := shift(&w2, date, planner.NewClock(6, 0), planner.NewClock(10, 0))
+	night := shift(&w1, to, planner.NewClock(22, 0), planner.NewClock(6, 0))
+	open := shift(nil, date.AddDate(0, 0, 1), planner.NewClock(8, 0), planner.NewClock(12, 0))
+	workers := []planner.Worker{{ID: w2, Name: "Ann"}, {ID: w1, Name: "Bob"}, {ID: w3, Name: "Eve"}}
+
+	tests := []struct {
+		name   string
+		filter export.Filter
+		expect func(ctx context.Context, repo *repomock.MockRepository)
+		want   export.Roster
+		expErr error
+	}{
+		{
+			name:   "All locations",
+			filter: export.Filter{From: date.Add(time.Hour), To: to},
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				repo.EXPECT().Locations(ctx).Return([]planner.Location{location}, nil)
+				repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{
+					From: &date, To: &to, Sort: planner.Sort{Field: planner.SortByDate}, Limit: planner.MaxLimit + 1,
+				}).Return([]planner.Shift{late, early, open, night}, nil)
+				repo.EXPECT().Workers(ctx, planner.WorkersFilter{
+					Sort: planner.Sort{Field: planner.SortByName}, Limit: planner.MaxLimit + 1,
+				}).Return(workers, nil)
+			},
+			want: export.Roster{
+				Title: "Roster 2025-11-03 - 2025-11-05",
+				Days:  []time.Time{date, date.AddDate(0, 0, 1), to},
+				Rows: []export.Row{
+					{WorkerID: w2, Name: "Ann", Cells: [][]export.Entry{
+						{
+							{ShiftID: early.ID, Start: early.Start, End: early.End},
+							{ShiftID: late.ID, Start: late.Start, End: late.End, Location: "North"},
+						},
+						nil, nil,
+					}},
+					{WorkerID: w1, Name: "Bob", Cells: [][]export.Entry{
+						nil, nil, {{ShiftID: night.ID, Start: night.Start, End: night.End}},
+					}},
+					{WorkerID: uuid.Nil, Name: "Open shifts", Cells: [][]export.Entry{
+						nil, {{ShiftID: open.ID, Start: open.Start, End: open.End}}, nil,
+					}},
+				},
+			},
+		},
+		{
+			name:   "Unknown location",
+			filter: export.Filter{LocationID: &w1, From: date, To: to},
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				repo.EXPECT().Location(ctx, w1).Return(planner.Location{}, planner.ErrNoRecord)
+			},
+			expErr: planner.ErrNoRecord,
+		},
+		{
+			name:   "Invalid range",
+			filter: export.Filter{From: to, To: date},
+			expErr: planner.ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+			if tt.expect != nil {
+				tt.expect(ctx, repo)
+			}
+
+			roster, err := export.New(planner.New(repo)).Roster(ctx, tt.filter)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, roster)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	roster := export.Roster{
+		Title: "Roster 2025-11-03 - 2025-11-04",
+		Days:  []time.Time{date, date.AddDate(0, 0, 1)},
+		Rows: []export.Row{
+			{WorkerID: uuid.New(), Name: "Ann <Lead>", Cells: [][]export.Entry{
+				{
+					{Start: planner.NewClock(6, 0), End: planner.NewClock(10, 0)},
+					{Start: planner.NewClock(18, 0), End: planner.NewClock(22, 0), Location: "North, Gate 2"},
+				},
+				nil,
+			}},
+			{Name: "Open shifts", Cells: [][]export.Entry{nil, {{Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)}}}},
+			// names aren't evaluated as formulas by spreadsheet apps
+			{WorkerID: uuid.New(), Name: `=HYPERLINK("http://example.com","Eve")`, Cells: [][]export.Entry{
+				nil, {{Start: planner.NewClock(8, 0), End: planner.NewClock(12, 0), Location: "@Gate"}},
+			}},
+		},
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, roster.Write(&b, export.CSV))
+	assert.Equal(t, strings.Join([]string{
+		"Worker,Mon 2025-11-03,Tue 2025-11-04",
+		`Ann <Lead>,"06:00-10:00; 18:00-22:00 North, Gate 2",`,
+		"Open shifts,,22:00-06:00",
+		`"'=HYPERLINK(""http://example.com"",""Eve"")",,08:00-12:00 @Gate`,
+		"",
+	}, "\n"), b.String())
+
+	b.Reset()
+	assert.NoError(t, roster.Write(&b, export.XLSX))
+	file, err := excelize.OpenReader(&b)
+	assert.NoError(t, err)
+	rows, err := file.GetRows("Roster")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Worker", "Mon 2025-11-03", "Tue 2025-11-04"},
+		{"Ann <Lead>", "06:00-10:00\n18:00-22:00 North, Gate 2"},
+		{"Open shifts", "", "22:00-06:00"},
+		{`=HYPERLINK("http://example.com","Eve")`, "", "08:00-12:00 @Gate"},
+	}, rows)
+	// names are stored as text, not as formulas
+	formula, err := file.GetCellFormula("Roster", "A4")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+
+	b.Reset()
+	assert.NoError(t, roster.Write(&b, export.HTML))
+	html := b.String()
+	assert.Contains(t, html, "<title>Roster 2025-11-03 - 2025-11-04</title>")
+	assert.Contains(t, html, "<th>Worker</th><th>Mon 2025-11-03</th><th>Tue 2025-11-04</th>")
+	// names are escaped
+	assert.Contains(t, html, "<tr><th>Ann &lt;Lead&gt;</th><td>06:00-10:00\n18:00-22:00 North, Gate 2</td><td></td></tr>")
+	// printed names are kept as is
+	assert.Contains(t, html, "<th>=HYPERLINK(&#34;http://example.com&#34;,&#34;Eve&#34;)</th>")
+
+	assert.Error(t, roster.Write(&b, "pdf"))
+}
@@ -0,0 +1,386 @@
+// Package importer creates workers and shifts from CSV and XLSX files.
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/xuri/excelize/v2"
+)
+
+var ErrInvalidFile = errors.New("invalid import file")
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+type Kind string
+
+const (
+	Workers Kind = "workers"
+	Shifts  Kind = "shifts"
+)
+
+// columns lists columns of every kind, required ones are marked with true.
+var columns = map[Kind]map[string]bool{
+	Workers: {"name": true, "skills": false, "location_id": false},
+	Shifts: {
+		"worker_id": false, "date": true, "start": true, "end": true,
+		"skills": false, "location_id": false,
+	},
+}
+
+// xlsxRowBytes is a generous size of unzipped XML of a row with all columns,
+// shared strings of the row included.
+const xlsxRowBytes = 4 << 10
+
+// xlsxUnzipLimit limits unzipped size of XLSX files to the largest batch and
+// the rest of workbook parts, so small compressed files can't unzip to gigabytes.
+// Parts are never spilled to temporary files under the limit.
+const xlsxUnzipLimit = (planner.MaxBatchSize+1)*xlsxRowBytes + 1<<20
+
+// skillSeparator separates skills in a cell, so cells don't need quoting in CSV.
+const skillSeparator = ";"
+
+// Report describes result of the import, rows are numbered as lines
+// of the file starting from one.
+type Report struct {
+	DryRun bool `json:"dry_run"`
+	// Created is a number of created records, records are created only
+	// if all rows are valid.
+	Created int              `json:"created"`
+	Errors  []RowError       `json:"errors,omitempty"`
+	Workers []planner.Worker `json:"workers,omitempty"`
+	Shifts  []planner.Shift  `json:"shifts,omitempty"`
+}
+
+type RowError struct {
+	Row int `json:"row"`
+	// Column is set for errors of a single cell.
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+	// ConflictRow is set when shift conflicts with a shift of another row.
+	ConflictRow *int `json:"conflict_row,omitempty"`
+	// Shift is a stored shift the row conflicts with.
+	Shift      *planner.Shift      `json:"shift,omitempty"`
+	Violations []planner.Violation `json:"violations,omitempty"`
+}
+
+type Importer struct {
+	plan     planner.Work
+	validate *validator.Validate
+}
+
+// New returns Importer which validates rows with binding tags
+// the same way HTTP handlers validate request bodies.
+func New(plan planner.Work) Importer {
+	validate := validator.New()
+	validate.SetTagName("binding")
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("json"), ",")[0]
+	})
+	return Importer{plan: plan, validate: validate}
+}
+
+// Import reads records of the kind from file and creates them in one transaction.
+// Invalid rows are listed in report and nothing is created, dry run checks rows
+// without storing them.
+func (i Importer) Import(ctx context.Context, kind Kind, format Format, r io.Reader, dryRun bool) (Report, error) {
+	if _, ok := columns[kind]; !ok {
+		return Report{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidFile, kind)
+	}
+	rows, err := readRows(r, format)
+	if err != nil {
+		return Report{}, err
+	}
+	// header is the first non-blank row
+	first := 0
+	for first < len(rows) && blank(rows[first]) {
+		first++
+	}
+	if first == len(rows) {
+		return Report{}, fmt.Errorf("%w: no header", ErrInvalidFile)
+	}
+	header, err := parseHeader(kind, rows[first])
+	if err != nil {
+		return Report{}, err
+	}
+
+	plan := i.plan
+	if dryRun {
+		plan = plan.DryRun()
+	}
+	report := Report{DryRun: dryRun}
+	// lines maps batch indices to file rows as blank rows are skipped
+	var lines []int
+	var workers []planner.Worker
+	var shifts []planner.Shift
+	for n := first + 1; n < len(rows); n++ {
+		row := rows[n]
+		if blank(row) {
+			continue
+		}
+		line := n + 1
+		cells := make(map[string]string, len(header))
+		for j, cell := range row {
+			if j < len(header) {
+				cells[header[j]] = strings.TrimSpace(cell)
+			}
+		}
+		var rowErrs []RowError
+		switch kind {
+		case Workers:
+			var worker planner.Worker
+			worker, rowErrs = i.worker(line, cells)
+			workers = append(workers, worker)
+		case Shifts:
+			var shift planner.Shift
+			shift, rowErrs = i.shift(line, cells)
+			shifts = append(shifts, shift)
+		}
+		report.Errors = append(report.Errors, rowErrs...)
+		lines = append(lines, line)
+	}
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	switch kind {
+	case Workers:
+		report.Workers, err = plan.CreateWorkers(ctx, workers)
+		report.Created = len(report.Workers)
+	case Shifts:
+		report.Shifts, err = plan.CreateShifts(ctx, shifts)
+		report.Created = len(report.Shifts)
+	}
+	var batchErr planner.BatchError
+	if errors.As(err, &batchErr) {
+		report.Errors = batchErrors(batchErr, lines)
+		return report, nil
+	}
+	if err != nil {
+		return Report{}, fmt.Errorf("import %s: %w", kind, err)
+	}
+	return report, nil
+}
+
+func (i Importer) worker(line int, cells map[string]string) (planner.Worker, []RowError) {
+	worker := planner.Worker{Name: cells["name"], Skills: skills(cells["skills"])}
+	var errs []RowError
+	if id, err := optionalID(cells["location_id"]); err != nil {
+		errs = append(errs, RowError{Row: line, Column: "location_id", Error: err.Error()})
+	} else {
+		worker.LocationID = id
+	}
+	return worker, append(errs, i.validationErrors(line, worker)...)
+}
+
+func (i Importer) shift(line int, cells map[string]string) (planner.Shift, []RowError) {
+	var shift planner.Shift
+	var errs []RowError
+	cellErr := func(column string, err error) {
+		errs = append(errs, RowError{Row: line, Column: column, Error: err.Error()})
+	}
+	if id, err := optionalID(cells["worker_id"]); err != nil {
+		cellErr("worker_id", err)
+	} else if id != nil {
+		shift.WorkerID = id
+	}
+	if id, err := optionalID(cells["location_id"]); err != nil {
+		cellErr("location_id", err)
+	} else {
+		shift.LocationID = id
+	}
+	var err error
+	if shift.Date, err = parseDate(cells["date"]); err != nil {
+		cellErr("date", err)
+	}
+	if shift.Start, err = parseClock(cells["start"]); err != nil {
+		cellErr("start", err)
+	}
+	if shift.End, err = parseClock(cells["end"]); err != nil {
+		cellErr("end", err)
+	}
+	shift.Skills = skills(cells["skills"])
+	// values of unparsed cells are zero, so they would be reported twice
+	if len(errs) > 0 {
+		return shift, errs
+	}
+	return shift, i.validationErrors(line, shift)
+}
+
+func (i Importer) validationErrors(line int, record any) []RowError {
+	err := i.validate.Struct(record)
+	ve := validator.ValidationErrors{}
+	if !errors.As(err, &ve) {
+		return nil
+	}
+	errs := make([]RowError, 0, len(ve))
+	for _, fe := range ve {
+		errs = append(errs, RowError{Row: line, Column: fe.Field(), Error: "invalid field: " + fe.Tag()})
+	}
+	return errs
+}
+
+func batchErrors(batchErr planner.BatchError, lines []int) []RowError {
+	errs := make([]RowError, 0, len(batchErr.Items))
+	for _, item := range batchErr.Items {
+		rowErr := RowError{Row: lines[item.Index], Error: item.Err.Error()}
+		var conflictErr planner.ConflictError
+		if errors.As(item.Err, &conflictErr) {
+			// shifts of the file are not created, so they are referenced by row
+			if item.ConflictIndex != nil {
+				rowErr.Error = conflictErr.Reason.Error()
+				rowErr.ConflictRow = &lines[*item.ConflictIndex]
+			} else {
+				rowErr.Shift = &conflictErr.Shift
+			}
+		}
+		var complianceErr planner.ComplianceError
+		if errors.As(item.Err, &complianceErr) {
+			rowErr.Violations = complianceErr.Violations
+		}
+		errs = append(errs, rowErr)
+	}
+	return errs
+}
+
+func readRows(r io.Reader, format Format) ([][]string, error) {
+	switch format {
+	case CSV:
+		reader := csv.NewReader(r)
+		// rows are checked against header after blank ones are skipped
+		reader.FieldsPerRecord = -1
+		var rows [][]string
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+			}
+			// reader skips empty lines, they are kept as blank rows
+			// so rows are numbered as lines of the file
+			line, _ := reader.FieldPos(0)
+			for len(rows) < line-1 {
+				rows = append(rows, nil)
+			}
+			rows = append(rows, record)
+		}
+	case XLSX:
+		file, err := excelize.OpenReader(r, excelize.Options{
+			UnzipSizeLimit: xlsxUnzipLimit, UnzipXMLSizeLimit: xlsxUnzipLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		defer file.Close()
+		// raw values keep dates and times independent of cell formatting
+		rows, err := file.GetRows(file.GetSheetName(0), excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidFile, format)
+	}
+}
+
+func parseHeader(kind Kind, row []string) ([]string, error) {
+	header := make([]string, len(row))
+	seen := map[string]bool{}
+	for j, cell := range row {
+		column := strings.ToLower(strings.TrimSpace(cell))
+		if _, ok := columns[kind][column]; !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidFile, cell)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidFile, cell)
+		}
+		seen[column] = true
+		header[j] = column
+	}
+	for column, required := range columns[kind] {
+		if required && !seen[column] {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidFile, column)
+		}
+	}
+	return header, nil
+}
+
+func blank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func skills(cell string) []string {
+	if cell == "" {
+		return nil
+	}
+	return strings.Split(cell, skillSeparator)
+}
+
+func optionalID(cell string) (*uuid.UUID, error) {
+	if cell == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(cell)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// parseDate parses YYYY-MM-DD, RFC3339 or spreadsheet date serial number.
+func parseDate(cell string) (time.Time, error) {
+	if cell == "" {
+		return time.Time{}, errors.New("required")
+	}
+	if date, err := time.Parse(time.DateOnly, cell); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, cell); err == nil {
+		return date, nil
+	}
+	if serial, err := strconv.ParseFloat(cell, 64); err == nil {
+		date, err := excelize.ExcelDateToTime(serial, false)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q: expected YYYY-MM-DD", cell)
+}
+
+// parseClock parses HH:MM or spreadsheet time as a fraction of day.
+func parseClock(cell string) (planner.Clock, error) {
+	if cell == "" {
+		return 0, errors.New("required")
+	}
+	clock, err := planner.ParseClock(cell)
+	if err == nil {
+		return clock, nil
+	}
+	if fraction, ferr := strconv.ParseFloat(cell, 64); ferr == nil && fraction >= 0 && fraction <= 1 {
+		return planner.Clock(math.Round(fraction * planner.MinutesInDay)), nil
+	}
+	return 0, err
+}
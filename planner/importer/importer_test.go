@@ -0,0 +1,230 @@
+package importer_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/importer"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+type transaction = func(planner.Repository) error
+
+func TestImportWorkers(t *testing.T) {
+	t.Parallel()
+	locationID, missingID := uuid.New(), uuid.New()
+	id := uuid.New()
+	plan := func(repo planner.Repository) planner.Work {
+		return planner.New(repo, planner.UUIDGenerator(func() uuid.UUID { return id }))
+	}
+
+	tests := []struct {
+		name   string
+		file   string
+		dryRun bool
+		expect func(ctx context.Context, repo *repomock.MockRepository)
+		want   importer.Report
+		expErr error
+	}{
+		{
+			name: "Created",
+			file: "Name,Skills,Location_ID\n" +
+				"Ann,Forklift; cashier," + locationID.String() + "\n",
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+					repo.EXPECT().Location(ctx, locationID).Return(planner.Location{ID: locationID}, nil),
+					repo.EXPECT().CreateWorker(ctx, gomock.Any()).Return(nil),
+					repo.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil),
+				)
+			},
+			want: importer.Report{Created: 1, Workers: []planner.Worker{
+				{ID: id, Name: "Ann", Skills: []string{"cashier", "forklift"}, LocationID: &locationID},
+			}},
+		},
+		{
+			name:   "Dry run",
+			file:   "name\nAnn\n",
+			dryRun: true,
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error {
+						err := f(repo)
+						assert.Error(t, err)
+						return err
+					}),
+					repo.EXPECT().CreateWorker(ctx, gomock.Any()).Return(nil),
+					repo.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil),
+				)
+			},
+			want: importer.Report{DryRun: true, Created: 1, Workers: []planner.Worker{{ID: id, Name: "Ann"}}},
+		},
+		{
+			name: "Invalid rows",
+			file: "name,skills,location_id\n,forklift,\nBob,,north\n",
+			want: importer.Report{Errors: []importer.RowError{
+				{Row: 2, Column: "name", Error: "invalid field: required"},
+				{Row: 3, Column: "location_id", Error: "invalid UUID length: 5"},
+			}},
+		},
+		{
+			name: "Rejected by planner",
+			// blank rows are skipped, but row numbers are kept
+			file: "name,location_id\nAnn,\n\nBob," + missingID.String() + "\n",
+			expect: func(ctx context.Context, repo *repomock.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+					repo.EXPECT().CreateWorker(ctx, gomock.Any()).Return(nil),
+					repo.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil),
+					repo.EXPECT().Location(ctx, missingID).Return(planner.Location{}, planner.ErrNoRecord),
+				)
+			},
+			want: importer.Report{Errors: []importer.RowError{{Row: 4, Error: "location: no record"}}},
+		},
+		{name: "Unknown column", file: "name,age\nAnn,30\n", expErr: importer.ErrInvalidFile},
+		{name: "Missing column", file: "skills\nforklift\n", expErr: importer.ErrInvalidFile},
+		{name: "Duplicate column", file: "name,Name\nAnn,Ann\n", expErr: importer.ErrInvalidFile},
+		{name: "Empty file", expErr: importer.ErrInvalidFile},
+		{name: "No rows", file: "name\n", expErr: planner.ErrInvalidBatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+			if tt.expect != nil {
+				tt.expect(ctx, repo)
+			}
+
+			report, err := importer.New(plan(repo)).
+				Import(ctx, importer.Workers, importer.CSV, strings.NewReader(tt.file), tt.dryRun)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, report)
+		})
+	}
+}
+
+func TestImportShifts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	workerID := uuid.New()
+	file := "worker_id,date,start,end,skills\n" +
+		workerID.String() + ",2025-11-03,08:00,16:00,\n" +
+		",2025-11-03T00:00:00Z,08:00,08:00,\n" +
+		",03.11.2025,8am,16:00,\n" +
+		"nobody,2025-11-03,22:00,24:00,\n"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	report, err := importer.New(planner.New(repo)).Import(ctx, importer.Shifts, importer.CSV, strings.NewReader(file), false)
+	assert.NoError(t, err)
+	// nothing is created if any row is invalid
+	assert.Equal(t, importer.Report{Errors: []importer.RowError{
+		{Row: 3, Column: "end", Error: "invalid field: nefield"},
+		{Row: 4, Column: "date", Error: `date "03.11.2025": expected YYYY-MM-DD`},
+		{Row: 4, Column: "start", Error: `clock "8am": expected HH:MM`},
+		{Row: 5, Column: "worker_id", Error: "invalid UUID length: 6"},
+	}}, report)
+
+	_, err = importer.New(planner.New(repo)).Import(ctx, importer.Shifts, "ods", strings.NewReader(file), false)
+	assert.ErrorIs(t, err, importer.ErrInvalidFile)
+}
+
+func TestImportShiftsXLSX(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	next := 0
+	seqID := func() uuid.UUID {
+		next++
+		return ids[next-1]
+	}
+
+	file := excelize.NewFile()
+	sheet := file.GetSheetName(0)
+	assert.NoError(t, file.SetSheetRow(sheet, "A1", &[]any{"Date", "Start", "End", "Skills"}))
+	// dates and times of spreadsheets are numbers formatted as text
+	assert.NoError(t, file.SetSheetRow(sheet, "A2", &[]any{date, 0.25, 0.5, "Forklift"}))
+	assert.NoError(t, file.SetSheetRow(sheet, "A3", &[]any{"2025-11-04", "22:00", "06:00"}))
+	buf, err := file.WriteToBuffer()
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().CreateShift(ctx, gomock.Any()).Return(nil),
+		repo.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil),
+		repo.EXPECT().CreateShift(ctx, gomock.Any()).Return(nil),
+		repo.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil),
+	)
+
+	report, err := importer.New(planner.New(repo, planner.UUIDGenerator(seqID))).
+		Import(ctx, importer.Shifts, importer.XLSX, bytes.NewReader(buf.Bytes()), false)
+	assert.NoError(t, err)
+	assert.Equal(t, importer.Report{Created: 2, Shifts: []planner.Shift{
+		{ID: ids[0], Date: date, Start: planner.NewClock(6, 0), End: planner.NewClock(12, 0), Skills: []string{"forklift"}},
+		{ID: ids[1], Date: date.AddDate(0, 0, 1), Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)},
+	}}, report)
+}
+
+func TestImportXLSXUnzipLimit(t *testing.T) {
+	t.Parallel()
+	file := excelize.NewFile()
+	assert.NoError(t, file.SetSheetRow(file.GetSheetName(0), "A1", &[]any{"Name"}))
+	buf, err := file.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// workbook gets a sheet of blank space which compresses about a thousand times
+	src, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	bomb := bytes.Buffer{}
+	dst := zip.NewWriter(&bomb)
+	for _, f := range src.File {
+		w, err := dst.Create(f.Name)
+		assert.NoError(t, err)
+		r, err := f.Open()
+		assert.NoError(t, err)
+		_, err = io.Copy(w, r)
+		assert.NoError(t, err)
+		r.Close()
+	}
+	w, err := dst.Create("xl/worksheets/sheet2.xml")
+	assert.NoError(t, err)
+	_, err = w.Write(bytes.Repeat([]byte(" "), 64<<20))
+	assert.NoError(t, err)
+	assert.NoError(t, dst.Close())
+	assert.Less(t, bomb.Len(), 1<<20)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	_, err = importer.New(planner.New(repo)).
+		Import(context.Background(), importer.Workers, importer.XLSX, bytes.NewReader(bomb.Bytes()), false)
+	assert.ErrorIs(t, err, importer.ErrInvalidFile)
+	assert.ErrorContains(t, err, "unzip size")
+}
@@ -0,0 +1,212 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LeaveStatus string
+
+const (
+	LeavePending   LeaveStatus = "pending"
+	LeaveApproved  LeaveStatus = "approved"
+	LeaveRejected  LeaveStatus = "rejected"
+	LeaveCancelled LeaveStatus = "cancelled"
+)
+
+// leaveActions names status changes in audit log.
+var leaveActions = map[LeaveStatus]string{
+	LeaveApproved:  "approve",
+	LeaveRejected:  "reject",
+	LeaveCancelled: "cancel",
+}
+
+// leaveTransitions lists statuses leave request can move to from the status.
+var leaveTransitions = map[LeaveStatus][]LeaveStatus{
+	LeavePending: {LeaveApproved, LeaveRejected, LeaveCancelled},
+}
+
+func (s LeaveStatus) Valid() bool {
+	switch s {
+	case LeavePending, LeaveApproved, LeaveRejected, LeaveCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanBecome reports whether leave request in status s can be moved to next status.
+func (s LeaveStatus) CanBecome(next LeaveStatus) bool {
+	for _, status := range leaveTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// LeaveRequest is a time-off request of the worker,
+// once approved worker is unavailable for the time range.
+type LeaveRequest struct {
+	ID       uuid.UUID   `json:"id"`
+	WorkerID uuid.UUID   `json:"worker_id"`
+	From     time.Time   `json:"from" gorm:"column:starts_at" binding:"required"`
+	To       time.Time   `json:"to" gorm:"column:ends_at" binding:"required,gtfield=From"`
+	Reason   string      `json:"reason"`
+	Status   LeaveStatus `json:"status"`
+	TenantID string      `json:"-"`
+}
+
+// Unavailability returns time range of the leave as worker unavailability.
+func (l LeaveRequest) Unavailability() Unavailability {
+	return Unavailability{ID: l.ID, WorkerID: l.WorkerID, From: l.From, To: l.To, Reason: l.Reason}
+}
+
+type LeavesFilter struct {
+	WorkerID *uuid.UUID   `json:"worker_id"`
+	Status   *LeaveStatus `json:"status"`
+	// From and To select leave requests overlapping with the range.
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+}
+
+// LeaveApproval is an approved leave request with shifts
+// of the worker overlapping with the leave.
+type LeaveApproval struct {
+	Leave  LeaveRequest
+	Shifts []Shift
+	// Released is set when overlapping shifts were made open,
+	// Shifts are listed without the worker then.
+	Released bool
+}
+
+func (w Work) RequestLeave(ctx context.Context, leave LeaveRequest) (LeaveRequest, error) {
+	leave.ID = w.uuid()
+	leave.Status = LeavePending
+	leave.From = leave.From.UTC()
+	leave.To = leave.To.UTC()
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		if _, err := repo.Worker(ctx, leave.WorkerID); err != nil {
+			return fmt.Errorf("get worker: %w", err)
+		}
+		if err := repo.CreateLeave(ctx, leave); err != nil {
+			return fmt.Errorf("creating leave: %w", err)
+		}
+		return w.audit(ctx, repo, "leave", "create", leave.ID, nil, leave)
+	})
+	if err != nil {
+		return LeaveRequest{}, fmt.Errorf("request leave transaction: %w", err)
+	}
+	return leave, nil
+}
+
+func (w Work) Leave(ctx context.Context, id uuid.UUID) (LeaveRequest, error) {
+	leave, err := w.repo.Leave(ctx, id)
+	if err != nil {
+		return LeaveRequest{}, fmt.Errorf("get leave: %w", err)
+	}
+	return leave, nil
+}
+
+func (w Work) Leaves(ctx context.Context, filter LeavesFilter) ([]LeaveRequest, error) {
+	if filter.Status != nil && !filter.Status.Valid() {
+		return nil, fmt.Errorf("status %q: %w", *filter.Status, ErrInvalidFilter)
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, ErrInvalidRange
+	}
+	leaves, err := w.repo.Leaves(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list leaves: %w", err)
+	}
+	return leaves, nil
+}
+
+// ApproveLeave approves pending leave request and returns shifts of the worker
+// overlapping with the leave. If release is set overlapping shifts are unassigned,
+// so they stay in the roster as open shifts other workers can claim.
+func (w Work) ApproveLeave(ctx context.Context, id uuid.UUID, release bool) (LeaveApproval, error) {
+	approval := LeaveApproval{Shifts: []Shift{}, Released: release}
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		leave, err := w.moveLeave(ctx, repo, id, LeaveApproved)
+		if err != nil {
+			return err
+		}
+		approval.Leave = leave
+
+		// shifts starting on the previous day can last until the leave start
+		from, to := TruncateDate(leave.From).AddDate(0, 0, -1), TruncateDate(leave.To)
+		shifts, err := repo.Shifts(ctx, ShiftsFilter{WorkerID: &leave.WorkerID, From: &from, To: &to})
+		if err != nil {
+			return fmt.Errorf("list worker shifts: %w", err)
+		}
+		for _, s := range shifts {
+			if !s.StartTime().Before(leave.To) || !leave.From.Before(s.EndTime()) {
+				continue
+			}
+			if !release {
+				approval.Shifts = append(approval.Shifts, s)
+				continue
+			}
+			before := s
+			s.WorkerID = nil
+			if err := repo.UpdateShift(ctx, s); err != nil {
+				return fmt.Errorf("releasing shift %s: %w", s.ID, err)
+			}
+			if err := w.audit(ctx, repo, "shift", "release", s.ID, before, s); err != nil {
+				return err
+			}
+			approval.Shifts = append(approval.Shifts, s)
+		}
+		return nil
+	})
+	if err != nil {
+		return LeaveApproval{}, fmt.Errorf("approve leave transaction: %w", err)
+	}
+	return approval, nil
+}
+
+func (w Work) RejectLeave(ctx context.Context, id uuid.UUID) (LeaveRequest, error) {
+	return w.setLeaveStatus(ctx, id, LeaveRejected)
+}
+
+func (w Work) CancelLeave(ctx context.Context, id uuid.UUID) (LeaveRequest, error) {
+	return w.setLeaveStatus(ctx, id, LeaveCancelled)
+}
+
+func (w Work) setLeaveStatus(ctx context.Context, id uuid.UUID, status LeaveStatus) (LeaveRequest, error) {
+	var leave LeaveRequest
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		var err error
+		leave, err = w.moveLeave(ctx, repo, id, status)
+		return err
+	})
+	if err != nil {
+		return LeaveRequest{}, fmt.Errorf("%s leave transaction: %w", status, err)
+	}
+	return leave, nil
+}
+
+// moveLeave moves leave request to the next status if transition is allowed.
+func (w Work) moveLeave(ctx context.Context, repo Repository, id uuid.UUID, next LeaveStatus) (LeaveRequest, error) {
+	leave, err := repo.Leave(ctx, id)
+	if err != nil {
+		return LeaveRequest{}, fmt.Errorf("get leave: %w", err)
+	}
+	if !leave.Status.CanBecome(next) {
+		return LeaveRequest{}, fmt.Errorf("%s to %s: %w", leave.Status, next, ErrInvalidTransition)
+	}
+	// status is compared on update, so concurrent transitions can't both succeed
+	if err := repo.UpdateLeaveStatus(ctx, id, leave.Status, next); err != nil {
+		return LeaveRequest{}, fmt.Errorf("updating leave status: %w", err)
+	}
+	before := leave
+	leave.Status = next
+	if err := w.audit(ctx, repo, "leave", leaveActions[next], id, before, leave); err != nil {
+		return LeaveRequest{}, err
+	}
+	return leave, nil
+}
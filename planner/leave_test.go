@@ -0,0 +1,148 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestLeaveStatusTransitions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		from, to planner.LeaveStatus
+		want     bool
+	}{
+		{from: planner.LeavePending, to: planner.LeaveApproved, want: true},
+		{from: planner.LeavePending, to: planner.LeaveRejected, want: true},
+		{from: planner.LeavePending, to: planner.LeaveCancelled, want: true},
+		{from: planner.LeavePending, to: planner.LeavePending, want: false},
+		{from: planner.LeaveApproved, to: planner.LeaveCancelled, want: false},
+		{from: planner.LeaveRejected, to: planner.LeaveApproved, want: false},
+		{from: planner.LeaveCancelled, to: planner.LeavePending, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.from.CanBecome(tt.to))
+		})
+	}
+}
+
+func TestApproveLeave(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.New()
+	leaveID := uuid.New()
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := day.AddDate(0, 0, -1), day.AddDate(0, 0, 1)
+	// leave from noon till the end of the next day
+	leave := planner.LeaveRequest{
+		ID: leaveID, WorkerID: id1, From: day.Add(12 * time.Hour), To: day.AddDate(0, 0, 2), Status: planner.LeavePending,
+	}
+	before := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: day, Start: planner.NewClock(4, 0), End: planner.NewClock(12, 0)}
+	during := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: day, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	overnight := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: nextDay, Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)}
+	shifts := []planner.Shift{before, during, overnight}
+	to := day.AddDate(0, 0, 2)
+	openDuring, openOvernight := during, overnight
+	openDuring.WorkerID, openOvernight.WorkerID = nil, nil
+
+	tests := []struct {
+		name      string
+		status    planner.LeaveStatus
+		release   bool
+		updateErr error
+		want      []planner.Shift
+		expErr    error
+	}{
+		{name: "Report overlapping shifts", status: planner.LeavePending, want: []planner.Shift{during, overnight}},
+		{name: "Release overlapping shifts", status: planner.LeavePending, release: true, want: []planner.Shift{openDuring, openOvernight}},
+		{name: "Already rejected", status: planner.LeaveRejected, expErr: planner.ErrInvalidTransition},
+		{name: "Concurrently changed", status: planner.LeavePending, updateErr: planner.ErrInvalidTransition, expErr: planner.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			stored := leave
+			stored.Status = tt.status
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Leave(ctx, leaveID).Return(stored, nil),
+			}
+			if tt.status == planner.LeavePending {
+				expectations = append(
+					expectations,
+					repo.EXPECT().UpdateLeaveStatus(ctx, leaveID, planner.LeavePending, planner.LeaveApproved).Return(tt.updateErr),
+				)
+			}
+			if tt.expErr == nil {
+				expectations = append(
+					expectations,
+					expectAudit(ctx, repo, "leave", "approve", leaveID),
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &prevDay, To: &to}).Return(shifts, nil),
+				)
+			}
+			if tt.release {
+				expectations = append(
+					expectations,
+					repo.EXPECT().UpdateShift(ctx, openDuring).Return(nil),
+					expectAudit(ctx, repo, "shift", "release", during.ID),
+					repo.EXPECT().UpdateShift(ctx, openOvernight).Return(nil),
+					expectAudit(ctx, repo, "shift", "release", overnight.ID),
+				)
+			}
+			gomock.InOrder(expectations...)
+
+			plan := planner.New(repo)
+			result, err := plan.ApproveLeave(ctx, leaveID, tt.release)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, planner.LeaveApproved, result.Leave.Status)
+			assert.Equal(t, tt.want, result.Shifts)
+			assert.Equal(t, tt.release, result.Released)
+		})
+	}
+}
+
+func TestCreateShiftOnLeave(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1 := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	from, to := shift.StartTime(), shift.EndTime()
+	approved := planner.LeaveApproved
+	leave := planner.LeaveRequest{WorkerID: id1, From: date, To: date.AddDate(0, 0, 7), Status: planner.LeaveApproved}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		repo.EXPECT().Availabilities(ctx, planner.AvailabilityFilter{WorkerID: &id1}).Return(nil, nil),
+		repo.EXPECT().Unavailabilities(ctx, planner.UnavailabilityFilter{WorkerID: &id1, From: &from, To: &to}).Return(nil, nil),
+		repo.EXPECT().Leaves(ctx, planner.LeavesFilter{WorkerID: &id1, Status: &approved, From: &from, To: &to}).Return([]planner.LeaveRequest{leave}, nil),
+	)
+
+	plan := planner.New(repo, planner.UUIDGenerator(genID))
+	_, err := plan.CreateShift(ctx, shift)
+	assert.ErrorIs(t, err, planner.ErrWorkerUnavailable)
+}
@@ -0,0 +1,111 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Location is a site or department workers and shifts belong to.
+type Location struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name" binding:"required"`
+	TenantID string    `json:"-"`
+}
+
+func (w Work) CreateLocation(ctx context.Context, location Location) (Location, error) {
+	location.ID = w.uuid()
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		if err := repo.CreateLocation(ctx, location); err != nil {
+			return fmt.Errorf("creating location: %w", err)
+		}
+		return w.audit(ctx, repo, "location", "create", location.ID, nil, location)
+	})
+	if err != nil {
+		return Location{}, fmt.Errorf("create location transaction: %w", err)
+	}
+	return location, nil
+}
+
+func (w Work) Location(ctx context.Context, id uuid.UUID) (Location, error) {
+	location, err := w.repo.Location(ctx, id)
+	if err != nil {
+		return Location{}, fmt.Errorf("get location: %w", err)
+	}
+	return location, nil
+}
+
+func (w Work) Locations(ctx context.Context) ([]Location, error) {
+	locations, err := w.repo.Locations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list locations: %w", err)
+	}
+	return locations, nil
+}
+
+func (w Work) UpdateLocation(ctx context.Context, location Location) (Location, error) {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		before, err := repo.Location(ctx, location.ID)
+		if err != nil {
+			return fmt.Errorf("get location: %w", err)
+		}
+		if err := repo.UpdateLocation(ctx, location); err != nil {
+			return fmt.Errorf("updating location: %w", err)
+		}
+		return w.audit(ctx, repo, "location", "update", location.ID, before, location)
+	})
+	if err != nil {
+		return Location{}, fmt.Errorf("update location transaction: %w", err)
+	}
+	return location, nil
+}
+
+// DeleteLocation removes location which has no workers and shifts,
+// otherwise ErrLocationInUse is returned.
+func (w Work) DeleteLocation(ctx context.Context, id uuid.UUID) error {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		location, err := repo.Location(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get location: %w", err)
+		}
+		workers, err := repo.Workers(ctx, WorkersFilter{LocationID: &id, Limit: 1})
+		if err != nil {
+			return fmt.Errorf("list location workers: %w", err)
+		}
+		if len(workers) > 0 {
+			return fmt.Errorf("worker %s: %w", workers[0].ID, ErrLocationInUse)
+		}
+		shifts, err := repo.Shifts(ctx, ShiftsFilter{LocationID: &id, Limit: 1})
+		if err != nil {
+			return fmt.Errorf("list location shifts: %w", err)
+		}
+		if len(shifts) > 0 {
+			return fmt.Errorf("shift %s: %w", shifts[0].ID, ErrLocationInUse)
+		}
+		if err := repo.DeleteLocation(ctx, id); err != nil {
+			return fmt.Errorf("deleting location: %w", err)
+		}
+		return w.audit(ctx, repo, "location", "delete", id, location, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("delete location transaction: %w", err)
+	}
+	return nil
+}
+
+// checkLocation verifies that location exists, nil location is not checked.
+func checkLocation(ctx context.Context, repo Repository, id *uuid.UUID) error {
+	if id == nil {
+		return nil
+	}
+	_, err := repo.Location(ctx, *id)
+	if errors.Is(err, ErrNoRecord) {
+		return fmt.Errorf("location: %w", ErrNoRecord)
+	}
+	if err != nil {
+		return fmt.Errorf("get location: %w", err)
+	}
+	return nil
+}
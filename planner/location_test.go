@@ -0,0 +1,96 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestDeleteLocation(t *testing.T) {
+	t.Parallel()
+	locationID := uuid.New()
+	workerFilter := planner.WorkersFilter{LocationID: &locationID, Limit: 1}
+	shiftFilter := planner.ShiftsFilter{LocationID: &locationID, Limit: 1}
+
+	tests := []struct {
+		name    string
+		workers []planner.Worker
+		shifts  []planner.Shift
+		expErr  error
+	}{
+		{name: "Success"},
+		{name: "Has workers", workers: []planner.Worker{{ID: uuid.New()}}, expErr: planner.ErrLocationInUse},
+		{name: "Has shifts", shifts: []planner.Shift{{ID: uuid.New()}}, expErr: planner.ErrLocationInUse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			calls := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Location(ctx, locationID).Return(planner.Location{ID: locationID}, nil),
+				repo.EXPECT().Workers(ctx, workerFilter).Return(tt.workers, nil),
+			}
+			if len(tt.workers) == 0 {
+				calls = append(calls, repo.EXPECT().Shifts(ctx, shiftFilter).Return(tt.shifts, nil))
+			}
+			if tt.expErr == nil {
+				calls = append(
+					calls,
+					repo.EXPECT().DeleteLocation(ctx, locationID).Return(nil),
+					expectAudit(ctx, repo, "location", "delete", locationID),
+				)
+			}
+			gomock.InOrder(calls...)
+
+			plan := planner.New(repo)
+			err := plan.DeleteLocation(ctx, locationID)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestConflictAcrossLocations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1 := uuid.New()
+	site, other := uuid.New(), uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := planner.Shift{WorkerID: &id1, LocationID: &site, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	booked := planner.Shift{ID: uuid.New(), WorkerID: &id1, LocationID: &other, Date: date, Start: planner.NewClock(12, 0), End: planner.NewClock(20, 0)}
+	from, to := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Location(ctx, site).Return(planner.Location{ID: site}, nil),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		expectAvailable(ctx, repo, id1),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &from, To: &to}).Return([]planner.Shift{booked}, nil),
+	)
+
+	plan := planner.New(repo, planner.UUIDGenerator(genID))
+	_, err := plan.CreateShift(ctx, shift)
+	conflictErr := planner.ConflictError{}
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, booked, conflictErr.Shift)
+}
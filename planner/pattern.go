@@ -0,0 +1,222 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxExpandDays limits length of date ranges patterns are expanded for.
+const MaxExpandDays = MaxRangeDays
+
+type PatternKind string
+
+const (
+	// PatternWeekly repeats shift on the same weekdays every week.
+	PatternWeekly PatternKind = "weekly"
+	// PatternRotation repeats OnDays of work followed by OffDays of rest
+	// starting from Anchor date.
+	PatternRotation PatternKind = "rotation"
+)
+
+type ShiftPattern struct {
+	ID       uuid.UUID   `json:"id"`
+	WorkerID uuid.UUID   `json:"worker_id" binding:"required"`
+	Kind     PatternKind `json:"kind" binding:"required,oneof=weekly rotation"`
+	Weekdays Weekdays    `json:"weekdays" binding:"required_if=Kind weekly"`
+	OnDays   int         `json:"on_days" binding:"required_if=Kind rotation,gte=0,lte=366"`
+	OffDays  int         `json:"off_days" binding:"gte=0,lte=366"`
+	Anchor   time.Time   `json:"anchor" binding:"required_if=Kind rotation"`
+	Start    Clock       `json:"start" gorm:"column:start_minute" binding:"gte=0,lte=1439"`
+	End      Clock       `json:"end" gorm:"column:end_minute" binding:"gte=1,lte=1440,nefield=Start"`
+	TenantID string      `json:"-"`
+}
+
+type PatternsFilter struct {
+	WorkerID *uuid.UUID `json:"worker_id"`
+}
+
+// Matches reports whether pattern has a shift on the date.
+func (p ShiftPattern) Matches(date time.Time) bool {
+	switch p.Kind {
+	case PatternWeekly:
+		return p.Weekdays.Has(date.Weekday())
+	case PatternRotation:
+		period := p.OnDays + p.OffDays
+		days := int(TruncateDate(date).Sub(TruncateDate(p.Anchor)).Hours() / 24)
+		// dates before anchor give negative remainder
+		return ((days%period)+period)%period < p.OnDays
+	default:
+		return false
+	}
+}
+
+// Shift returns pattern shift on the date.
+func (p ShiftPattern) Shift(date time.Time) Shift {
+	return Shift{WorkerID: &p.WorkerID, Date: TruncateDate(date), Start: p.Start, End: p.End}
+}
+
+// Weekdays is a set of weekdays stored as a bit mask.
+type Weekdays uint8
+
+var weekdayNames = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseWeekday parses case insensitive three letter weekday name, e.g. "mon".
+func ParseWeekday(name string) (time.Weekday, error) {
+	for d, dayName := range weekdayNames {
+		if strings.EqualFold(name, dayName) {
+			return time.Weekday(d), nil
+		}
+	}
+	return 0, fmt.Errorf("weekday %q: expected one of %v", name, weekdayNames)
+}
+
+func NewWeekdays(days ...time.Weekday) Weekdays {
+	var w Weekdays
+	for _, d := range days {
+		w |= 1 << d
+	}
+	return w
+}
+
+func (w Weekdays) Has(day time.Weekday) bool {
+	return w&(1<<day) != 0
+}
+
+func (w Weekdays) MarshalJSON() ([]byte, error) {
+	names := []string{}
+	for d, name := range weekdayNames {
+		if w.Has(time.Weekday(d)) {
+			names = append(names, name)
+		}
+	}
+	return json.Marshal(names)
+}
+
+func (w *Weekdays) UnmarshalJSON(data []byte) error {
+	names := []string{}
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("weekdays: %w", err)
+	}
+	*w = 0
+	for _, name := range names {
+		day, err := ParseWeekday(name)
+		if err != nil {
+			return err
+		}
+		*w |= NewWeekdays(day)
+	}
+	return nil
+}
+
+// Expansion is a result of pattern expansion into shifts.
+type Expansion struct {
+	Created []Shift
+	Skipped []SkippedDate
+}
+
+// SkippedDate is a date of pattern for which shift wasn't created.
+type SkippedDate struct {
+	Date time.Time
+	Err  error
+}
+
+func (w Work) CreatePattern(ctx context.Context, pattern ShiftPattern) (ShiftPattern, error) {
+	pattern.ID = w.uuid()
+	pattern.Anchor = TruncateDate(pattern.Anchor)
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		if _, err := repo.Worker(ctx, pattern.WorkerID); err != nil {
+			return fmt.Errorf("get worker: %w", err)
+		}
+		if err := repo.CreatePattern(ctx, pattern); err != nil {
+			return fmt.Errorf("creating pattern: %w", err)
+		}
+		return w.audit(ctx, repo, "pattern", "create", pattern.ID, nil, pattern)
+	})
+	if err != nil {
+		return ShiftPattern{}, fmt.Errorf("create pattern transaction: %w", err)
+	}
+	return pattern, nil
+}
+
+func (w Work) Pattern(ctx context.Context, id uuid.UUID) (ShiftPattern, error) {
+	pattern, err := w.repo.Pattern(ctx, id)
+	if err != nil {
+		return ShiftPattern{}, fmt.Errorf("get pattern: %w", err)
+	}
+	return pattern, nil
+}
+
+func (w Work) Patterns(ctx context.Context, filter PatternsFilter) ([]ShiftPattern, error) {
+	patterns, err := w.repo.Patterns(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list patterns: %w", err)
+	}
+	return patterns, nil
+}
+
+func (w Work) DeletePattern(ctx context.Context, id uuid.UUID) error {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		pattern, err := repo.Pattern(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get pattern: %w", err)
+		}
+		if err := repo.DeletePattern(ctx, id); err != nil {
+			return fmt.Errorf("deleting pattern: %w", err)
+		}
+		return w.audit(ctx, repo, "pattern", "delete", id, pattern, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("delete pattern transaction: %w", err)
+	}
+	return nil
+}
+
+// ExpandPattern creates pattern shifts for dates from the inclusive range shorter than MaxExpandDays.
+// Dates on which shift conflicts with existing ones are skipped and reported.
+func (w Work) ExpandPattern(ctx context.Context, id uuid.UUID, from, to time.Time) (Expansion, error) {
+	from, to, err := CheckRange(ctx, w.repo, from, to, nil)
+	if err != nil {
+		return Expansion{}, err
+	}
+	expansion := Expansion{}
+	err = w.repo.Transaction(ctx, func(repo Repository) error {
+		pattern, err := repo.Pattern(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get pattern: %w", err)
+		}
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if !pattern.Matches(date) {
+				continue
+			}
+			shift := pattern.Shift(date)
+			shift.ID = w.uuid()
+			err := w.checkShift(ctx, repo, &shift)
+			var planErr Error
+			switch {
+			case errors.As(err, &planErr):
+				expansion.Skipped = append(expansion.Skipped, SkippedDate{Date: date, Err: err})
+				continue
+			case err != nil:
+				return fmt.Errorf("date %s: %w", date.Format(time.DateOnly), err)
+			}
+			if err := repo.CreateShift(ctx, shift); err != nil {
+				return fmt.Errorf("creating shift: %w", err)
+			}
+			if err := w.audit(ctx, repo, "shift", "create", shift.ID, nil, shift); err != nil {
+				return err
+			}
+			expansion.Created = append(expansion.Created, shift)
+		}
+		return nil
+	})
+	if err != nil {
+		return Expansion{}, fmt.Errorf("expand pattern transaction: %w", err)
+	}
+	return expansion, nil
+}
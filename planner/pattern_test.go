@@ -0,0 +1,113 @@
+package planner_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestPatternMatches(t *testing.T) {
+	t.Parallel()
+	// Monday
+	anchor := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	weekly := planner.ShiftPattern{
+		Kind:     planner.PatternWeekly,
+		Weekdays: planner.NewWeekdays(time.Monday, time.Wednesday),
+	}
+	rotation := planner.ShiftPattern{Kind: planner.PatternRotation, OnDays: 4, OffDays: 4, Anchor: anchor}
+
+	tests := []struct {
+		name    string
+		pattern planner.ShiftPattern
+		date    time.Time
+		want    bool
+	}{
+		{name: "Weekly: monday", pattern: weekly, date: anchor, want: true},
+		{name: "Weekly: tuesday", pattern: weekly, date: anchor.AddDate(0, 0, 1), want: false},
+		{name: "Weekly: next wednesday", pattern: weekly, date: anchor.AddDate(0, 0, 9), want: true},
+		{name: "Rotation: first day", pattern: rotation, date: anchor, want: true},
+		{name: "Rotation: last on day", pattern: rotation, date: anchor.AddDate(0, 0, 3), want: true},
+		{name: "Rotation: first off day", pattern: rotation, date: anchor.AddDate(0, 0, 4), want: false},
+		{name: "Rotation: next period", pattern: rotation, date: anchor.AddDate(0, 0, 8), want: true},
+		{name: "Rotation: before anchor off", pattern: rotation, date: anchor.AddDate(0, 0, -1), want: false},
+		{name: "Rotation: before anchor on", pattern: rotation, date: anchor.AddDate(0, 0, -5), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.pattern.Matches(tt.date))
+		})
+	}
+}
+
+func TestWeekdaysJSON(t *testing.T) {
+	t.Parallel()
+	days := planner.Weekdays(0)
+	assert.NoError(t, json.Unmarshal([]byte(`["Mon", "fri"]`), &days))
+	assert.Equal(t, planner.NewWeekdays(time.Monday, time.Friday), days)
+
+	data, err := json.Marshal(days)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["mon", "fri"]`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`["monday"]`), &days))
+}
+
+func TestExpandPattern(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1 := uuid.New()
+	// Monday to Sunday
+	from := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+	pattern := planner.ShiftPattern{
+		ID:       fixedID,
+		WorkerID: id1,
+		Kind:     planner.PatternWeekly,
+		Weekdays: planner.NewWeekdays(time.Monday, time.Wednesday),
+		Start:    planner.NewClock(8, 0),
+		End:      planner.NewClock(16, 0),
+	}
+	wednesday := from.AddDate(0, 0, 2)
+	booked := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: wednesday, Start: planner.NewClock(18, 0), End: planner.NewClock(20, 0)}
+	monShift := planner.Shift{ID: fixedID, WorkerID: &id1, Date: from, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	plan := planner.New(repo, planner.UUIDGenerator(genID))
+	monFrom, monTo := from.AddDate(0, 0, -1), from.AddDate(0, 0, 1)
+	wedFrom, wedTo := wednesday.AddDate(0, 0, -1), wednesday.AddDate(0, 0, 1)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Pattern(ctx, fixedID).Return(pattern, nil),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		expectAvailable(ctx, repo, id1),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &monFrom, To: &monTo}).Return(nil, nil),
+		repo.EXPECT().CreateShift(ctx, monShift).Return(nil),
+		expectAudit(ctx, repo, "shift", "create", fixedID),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		expectAvailable(ctx, repo, id1),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &wedFrom, To: &wedTo}).Return([]planner.Shift{booked}, nil),
+	)
+
+	result, err := plan.ExpandPattern(ctx, fixedID, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []planner.Shift{monShift}, result.Created)
+	assert.Len(t, result.Skipped, 1)
+	assert.Equal(t, wednesday, result.Skipped[0].Date)
+	assert.ErrorIs(t, result.Skipped[0].Err, planner.ErrDayAlreadyBooked)
+
+	_, err = plan.ExpandPattern(ctx, fixedID, to, from)
+	assert.ErrorIs(t, err, planner.ErrInvalidRange)
+}
@@ -2,8 +2,12 @@ package planner
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,46 +20,347 @@ func (e Error) Error() string {
 }
 
 const (
-	ErrDayAlreadyBooked = Error("day already booked")
-	ErrNoRecord         = Error("no record")
+	ErrDayAlreadyBooked  = Error("day already booked")
+	ErrNoRecord          = Error("no record")
+	ErrInvalidShift      = Error("invalid shift")
+	ErrInvalidFilter     = Error("invalid filter")
+	ErrShiftsOverlap     = Error("shifts overlap")
+	ErrRestTooShort      = Error("rest between shifts too short")
+	ErrInvalidBatch      = Error("invalid batch size")
+	ErrInvalidRange      = Error("invalid date range")
+	ErrWorkerUnavailable = Error("worker unavailable")
+	ErrInvalidTransition = Error("invalid status transition")
+	ErrInvalidSwap       = Error("invalid swap")
+	ErrShiftTaken        = Error("shift already taken")
+	ErrUnqualified       = Error("worker lacks required skills")
+	ErrLocationInUse     = Error("location in use")
+	ErrNonCompliant      = Error("compliance rules violated")
 )
 
+// LocationGetter is implemented by Repository and Work.
+type LocationGetter interface {
+	Location(ctx context.Context, id uuid.UUID) (Location, error)
+}
+
+// MaxRangeDays limits length of date ranges patterns are expanded for
+// and reports, feeds and exports are built for.
+const MaxRangeDays = 366
+
+// CheckRange returns inclusive range of dates truncated to days.
+// Range must be shorter than MaxRangeDays and its location, if set, must exist.
+func CheckRange(
+	ctx context.Context, locations LocationGetter, from, to time.Time, locationID *uuid.UUID,
+) (time.Time, time.Time, error) {
+	from, to = TruncateDate(from), TruncateDate(to)
+	if to.Before(from) || !to.Before(from.AddDate(0, 0, MaxRangeDays)) {
+		return time.Time{}, time.Time{}, ErrInvalidRange
+	}
+	if locationID != nil {
+		if _, err := locations.Location(ctx, *locationID); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("get location: %w", err)
+		}
+	}
+	return from, to, nil
+}
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+	MaxBatchSize = 1000
+)
+
+type SortField string
+
+const (
+	SortByName   SortField = "name"
+	SortByID     SortField = "id"
+	SortByDate   SortField = "date"
+	SortByWorker SortField = "worker"
+)
+
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+// ParseSort parses sort query value, leading "-" means descending order.
+func ParseSort(s string) Sort {
+	if len(s) > 0 && s[0] == '-' {
+		return Sort{Field: SortField(s[1:]), Desc: true}
+	}
+	return Sort{Field: SortField(s)}
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// Cursor points to the last item of previous page,
+// it holds values of all fields lists can be sorted by.
+type Cursor struct {
+	Name     string    `json:"n,omitempty"`
+	Date     time.Time `json:"d"`
+	WorkerID uuid.UUID `json:"w"`
+	ID       uuid.UUID `json:"i"`
+}
+
+func ParseCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("decode cursor: %w", ErrInvalidFilter)
+	}
+	c := Cursor{}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, fmt.Errorf("unmarshal cursor: %w", ErrInvalidFilter)
+	}
+	return c, nil
+}
+
+func (c Cursor) String() string {
+	// marshaling of plain struct can't fail
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
 type Worker struct {
 	ID   uuid.UUID `json:"id"`
 	Name string    `json:"name" binding:"required"`
+	// Skills are qualifications and roles of the worker, e.g. forklift or supervisor.
+	Skills []string `json:"skills,omitempty" gorm:"serializer:json"`
+	// LocationID is a home location of the worker, it doesn't restrict
+	// shifts worker can take.
+	LocationID *uuid.UUID `json:"location_id,omitempty"`
+	TenantID   string     `json:"-"`
+}
+
+type WorkerPatch struct {
+	Name       *string    `json:"name" binding:"omitempty,min=1"`
+	Skills     *[]string  `json:"skills"`
+	LocationID *uuid.UUID `json:"location_id"`
 }
 
 type WorkersFilter struct {
 	Name *string `json:"name"`
+	// Skills selects workers having all of the skills.
+	Skills     []string   `json:"skills"`
+	LocationID *uuid.UUID `json:"location_id"`
+
+	// Sort is applied with ID as a tie-breaker, so order is always stable.
+	Sort Sort `json:"sort"`
+	// Limit of zero means no limit for repository.
+	Limit int     `json:"limit"`
+	After *Cursor `json:"after"`
 }
 
 type Shift struct {
-	ID        uuid.UUID `json:"id"`
-	WorkerID  uuid.UUID `json:"worker_id" binding:"required"`
-	Date      time.Time `json:"date" binding:"required"`
-	StartHour int       `json:"start_hour" binding:"gte=0,lte=23"`
-	EndHour   int       `json:"end_hour" binding:"gte=1,lte=24,gtfield=StartHour"`
+	ID uuid.UUID `json:"id"`
+	// WorkerID is nil for open shifts which can be claimed by any worker.
+	WorkerID *uuid.UUID `json:"worker_id"`
+	Date     time.Time  `json:"date" binding:"required"`
+	Start    Clock      `json:"start" gorm:"column:start_minute" binding:"gte=0,lte=1439"`
+	// End before Start means that shift ends on the next day.
+	End Clock `json:"end" gorm:"column:end_minute" binding:"gte=1,lte=1440,nefield=Start"`
+	// Skills are required from the worker of the shift.
+	Skills     []string   `json:"skills,omitempty" gorm:"serializer:json"`
+	LocationID *uuid.UUID `json:"location_id,omitempty"`
+	// Warnings are violations of compliance rules in warn mode caused by the change,
+	// they are not stored.
+	Warnings []Violation `json:"warnings,omitempty" gorm:"-"`
+	TenantID string      `json:"-"`
+}
+
+// Open reports whether shift has no worker assigned.
+func (s Shift) Open() bool {
+	return s.WorkerID == nil
+}
+
+// AssignedTo reports whether shift is assigned to the worker.
+func (s Shift) AssignedTo(workerID uuid.UUID) bool {
+	return s.WorkerID != nil && *s.WorkerID == workerID
+}
+
+func (s Shift) Overnight() bool {
+	return s.End < s.Start
+}
+
+func (s Shift) StartTime() time.Time {
+	return s.Date.Add(time.Duration(s.Start) * time.Minute)
+}
+
+func (s Shift) EndTime() time.Time {
+	end := s.Date.Add(time.Duration(s.End) * time.Minute)
+	if s.Overnight() {
+		return end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+func (s Shift) Duration() time.Duration {
+	return s.EndTime().Sub(s.StartTime())
+}
+
+func (s Shift) Overlaps(other Shift) bool {
+	return s.StartTime().Before(other.EndTime()) && other.StartTime().Before(s.EndTime())
+}
+
+// MarshalJSON adds start_hour and end_hour fields for clients
+// created before minute precision, hours are rounded to cover the shift.
+func (s Shift) MarshalJSON() ([]byte, error) {
+	type shift Shift
+	return json.Marshal(struct {
+		shift
+		StartHour int `json:"start_hour"`
+		EndHour   int `json:"end_hour"`
+	}{shift: shift(s), StartHour: s.Start.Hour(), EndHour: s.End.CeilHour()})
+}
+
+// UnmarshalJSON accepts start_hour and end_hour fields
+// for clients created before minute precision,
+// start and end fields take precedence over them.
+func (s *Shift) UnmarshalJSON(data []byte) error {
+	type shift Shift
+	aux := struct {
+		*shift
+		Start     *Clock `json:"start"`
+		End       *Clock `json:"end"`
+		StartHour *int   `json:"start_hour"`
+		EndHour   *int   `json:"end_hour"`
+	}{shift: (*shift)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	switch {
+	case aux.Start != nil:
+		s.Start = *aux.Start
+	case aux.StartHour != nil:
+		s.Start = NewClock(*aux.StartHour, 0)
+	}
+	switch {
+	case aux.End != nil:
+		s.End = *aux.End
+	case aux.EndHour != nil:
+		s.End = NewClock(*aux.EndHour, 0)
+	}
+	return nil
+}
+
+type ShiftPatch struct {
+	WorkerID   *uuid.UUID `json:"worker_id"`
+	Date       *time.Time `json:"date"`
+	Start      *Clock     `json:"start" binding:"omitempty,gte=0,lte=1439"`
+	End        *Clock     `json:"end" binding:"omitempty,gte=1,lte=1440"`
+	StartHour  *int       `json:"start_hour" binding:"omitempty,gte=0,lte=23"`
+	EndHour    *int       `json:"end_hour" binding:"omitempty,gte=1,lte=24"`
+	Skills     *[]string  `json:"skills"`
+	LocationID *uuid.UUID `json:"location_id"`
 }
 
 type ShiftsFilter struct {
 	WorkerID *uuid.UUID `json:"worker_id"`
-	Date     *time.Time `json:"date"`
+	// Open selects only shifts without worker.
+	Open       bool       `json:"open"`
+	LocationID *uuid.UUID `json:"location_id"`
+	Date       *time.Time `json:"date"`
+	// From and To are inclusive bounds of shift date range.
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+
+	// Sort by date is applied with worker and ID as tie-breakers,
+	// sort by worker - with date and ID.
+	Sort Sort `json:"sort"`
+	// Limit of zero means no limit for repository.
+	Limit int     `json:"limit"`
+	After *Cursor `json:"after"`
+}
+
+// DailyHours is a total of worker's shifts starting on the date.
+type DailyHours struct {
+	WorkerID uuid.UUID `json:"worker_id"`
+	Date     time.Time `json:"date"`
+	Shifts   int       `json:"shifts"`
+	Minutes  int       `json:"minutes"`
 }
 
 type Repository interface {
 	CreateWorker(ctx context.Context, worker Worker) error
 	Worker(ctx context.Context, id uuid.UUID) (Worker, error)
 	Workers(ctx context.Context, filter WorkersFilter) ([]Worker, error)
+	UpdateWorker(ctx context.Context, worker Worker) error
+	DeleteWorker(ctx context.Context, id uuid.UUID) error
 
 	CreateShift(ctx context.Context, shift Shift) error
+	Shift(ctx context.Context, id uuid.UUID) (Shift, error)
 	Shifts(ctx context.Context, filter ShiftsFilter) ([]Shift, error)
+	UpdateShift(ctx context.Context, shift Shift) error
+	DeleteShift(ctx context.Context, id uuid.UUID) error
+	DeleteShifts(ctx context.Context, filter ShiftsFilter) error
+	// ClaimShift assigns worker to the open shift,
+	// ErrShiftTaken is returned if shift isn't open.
+	ClaimShift(ctx context.Context, id, workerID uuid.UUID) error
+	// DailyHours sums shifts of each worker per date, open shifts are skipped.
+	// Sort and pagination of the filter are ignored.
+	DailyHours(ctx context.Context, filter ShiftsFilter) ([]DailyHours, error)
+
+	CreatePattern(ctx context.Context, pattern ShiftPattern) error
+	Pattern(ctx context.Context, id uuid.UUID) (ShiftPattern, error)
+	Patterns(ctx context.Context, filter PatternsFilter) ([]ShiftPattern, error)
+	DeletePattern(ctx context.Context, id uuid.UUID) error
+	DeletePatterns(ctx context.Context, filter PatternsFilter) error
+
+	CreateAvailability(ctx context.Context, availability Availability) error
+	Availability(ctx context.Context, id uuid.UUID) (Availability, error)
+	Availabilities(ctx context.Context, filter AvailabilityFilter) ([]Availability, error)
+	UpdateAvailability(ctx context.Context, availability Availability) error
+	DeleteAvailability(ctx context.Context, id uuid.UUID) error
+	DeleteAvailabilities(ctx context.Context, filter AvailabilityFilter) error
+
+	CreateUnavailability(ctx context.Context, unavailability Unavailability) error
+	Unavailability(ctx context.Context, id uuid.UUID) (Unavailability, error)
+	Unavailabilities(ctx context.Context, filter UnavailabilityFilter) ([]Unavailability, error)
+	UpdateUnavailability(ctx context.Context, unavailability Unavailability) error
+	DeleteUnavailability(ctx context.Context, id uuid.UUID) error
+	DeleteUnavailabilities(ctx context.Context, filter UnavailabilityFilter) error
+
+	CreateLeave(ctx context.Context, leave LeaveRequest) error
+	Leave(ctx context.Context, id uuid.UUID) (LeaveRequest, error)
+	Leaves(ctx context.Context, filter LeavesFilter) ([]LeaveRequest, error)
+	// UpdateLeaveStatus changes status of leave request which is in from status,
+	// otherwise ErrInvalidTransition is returned.
+	UpdateLeaveStatus(ctx context.Context, id uuid.UUID, from, to LeaveStatus) error
+	DeleteLeaves(ctx context.Context, filter LeavesFilter) error
+
+	CreateSwap(ctx context.Context, swap SwapRequest) error
+	Swap(ctx context.Context, id uuid.UUID) (SwapRequest, error)
+	Swaps(ctx context.Context, filter SwapsFilter) ([]SwapRequest, error)
+	// UpdateSwapStatus changes status of swap request which is in from status,
+	// otherwise ErrInvalidTransition is returned.
+	UpdateSwapStatus(ctx context.Context, id uuid.UUID, from, to SwapStatus) error
+	DeleteSwaps(ctx context.Context, filter SwapsFilter) error
+
+	CreateLocation(ctx context.Context, location Location) error
+	Location(ctx context.Context, id uuid.UUID) (Location, error)
+	Locations(ctx context.Context) ([]Location, error)
+	UpdateLocation(ctx context.Context, location Location) error
+	DeleteLocation(ctx context.Context, id uuid.UUID) error
+
+	// Audit log is append-only, entries can't be changed or deleted.
+	CreateAuditEntry(ctx context.Context, entry AuditEntry) error
+	AuditEntries(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+	// Revisions returns revisions of the entities which have audit entries.
+	Revisions(ctx context.Context, entity string, ids []uuid.UUID) ([]Revision, error)
 
 	Transaction(ctx context.Context, action func(Repository) error) error
 }
 
 type Work struct {
-	repo Repository
-	uuid func() uuid.UUID
+	repo      Repository
+	uuid      func() uuid.UUID
+	now       func() time.Time
+	conflicts ConflictPolicy
+	rules     []ComplianceRule
 }
 
 type Option func(w *Work)
@@ -66,10 +371,32 @@ func UUIDGenerator(gen func() uuid.UUID) Option {
 	}
 }
 
+// TimeSource sets clock used for audit entries and to find out current date.
+func TimeSource(now func() time.Time) Option {
+	return func(w *Work) {
+		w.now = now
+	}
+}
+
+func ConflictRule(policy ConflictPolicy) Option {
+	return func(w *Work) {
+		w.conflicts = policy
+	}
+}
+
+// Compliance sets rules checked on every shift change.
+func Compliance(rules ...ComplianceRule) Option {
+	return func(w *Work) {
+		w.rules = rules
+	}
+}
+
 func New(repo Repository, opts ...Option) Work {
 	work := Work{
-		repo: repo,
-		uuid: func() uuid.UUID { return uuid.New() },
+		repo:      repo,
+		uuid:      func() uuid.UUID { return uuid.New() },
+		now:       time.Now,
+		conflicts: OnePerDay{},
 	}
 	for _, opt := range opts {
 		opt(&work)
@@ -77,53 +404,289 @@ func New(repo Repository, opts ...Option) Work {
 	return work
 }
 
+// DryRun returns Work which makes all checks of changes, but rolls back
+// their transactions, so results of changes are returned without storing them.
+func (w Work) DryRun() Work {
+	w.repo = dryRunRepository{w.repo}
+	return w
+}
+
+// errRollback aborts transaction of dry run.
+var errRollback = errors.New("dry run rollback")
+
+type dryRunRepository struct {
+	Repository
+}
+
+func (r dryRunRepository) Transaction(ctx context.Context, action func(Repository) error) error {
+	err := r.Repository.Transaction(ctx, func(repo Repository) error {
+		if err := action(repo); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if errors.Is(err, errRollback) {
+		return nil
+	}
+	return err
+}
+
 func (w Work) CreateWorker(ctx context.Context, worker Worker) (Worker, error) {
 	worker.ID = w.uuid()
-	if err := w.repo.CreateWorker(ctx, worker); err != nil {
-		return Worker{}, fmt.Errorf("creating worker: %w", err)
+	worker.Skills = NormalizeSkills(worker.Skills)
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		if err := checkLocation(ctx, repo, worker.LocationID); err != nil {
+			return err
+		}
+		if err := repo.CreateWorker(ctx, worker); err != nil {
+			return fmt.Errorf("creating worker: %w", err)
+		}
+		return w.audit(ctx, repo, "worker", "create", worker.ID, nil, worker)
+	})
+	if err != nil {
+		return Worker{}, fmt.Errorf("create worker transaction: %w", err)
+	}
+	return worker, nil
+}
+
+// CreateWorkers creates all workers or none of them,
+// returned BatchError lists all rejected workers.
+func (w Work) CreateWorkers(ctx context.Context, workers []Worker) ([]Worker, error) {
+	if len(workers) == 0 || len(workers) > MaxBatchSize {
+		return nil, ErrInvalidBatch
+	}
+	created := make([]Worker, 0, len(workers))
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		batchErr := BatchError{}
+		for i, worker := range workers {
+			worker.ID = w.uuid()
+			worker.Skills = NormalizeSkills(worker.Skills)
+			err := checkLocation(ctx, repo, worker.LocationID)
+			var planErr Error
+			switch {
+			case errors.As(err, &planErr):
+				batchErr.Items = append(batchErr.Items, ItemError{Index: i, Err: err})
+				continue
+			case err != nil:
+				return fmt.Errorf("worker %d: %w", i, err)
+			}
+			if err := repo.CreateWorker(ctx, worker); err != nil {
+				return fmt.Errorf("creating worker %d: %w", i, err)
+			}
+			if err := w.audit(ctx, repo, "worker", "create", worker.ID, nil, worker); err != nil {
+				return err
+			}
+			created = append(created, worker)
+		}
+		if len(batchErr.Items) > 0 {
+			return batchErr
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create workers transaction: %w", err)
+	}
+	return created, nil
+}
+
+func (w Work) Worker(ctx context.Context, id uuid.UUID) (Worker, error) {
+	worker, err := w.repo.Worker(ctx, id)
+	if err != nil {
+		return Worker{}, fmt.Errorf("get worker: %w", err)
 	}
 	return worker, nil
 }
 
-func (w Work) Workers(ctx context.Context, filter WorkersFilter) ([]Worker, error) {
+// Workers returns a page of workers and a cursor of the next page,
+// the cursor is nil for the last page.
+func (w Work) Workers(ctx context.Context, filter WorkersFilter) ([]Worker, *Cursor, error) {
+	switch filter.Sort.Field {
+	case "":
+		filter.Sort.Field = SortByName
+	case SortByName, SortByID:
+	default:
+		return nil, nil, fmt.Errorf("sort %q: %w", filter.Sort, ErrInvalidFilter)
+	}
+	filter.Limit = pageLimit(filter.Limit)
+	// one extra record is requested to find out if there is a next page
+	filter.Limit++
+	filter.Skills = NormalizeSkills(filter.Skills)
+
 	workers, err := w.repo.Workers(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("list workers: %w", err)
+		return nil, nil, fmt.Errorf("list workers: %w", err)
 	}
-	return workers, nil
+	if len(workers) < filter.Limit {
+		return workers, nil, nil
+	}
+	workers = workers[:len(workers)-1]
+	last := workers[len(workers)-1]
+	return workers, &Cursor{Name: last.Name, ID: last.ID}, nil
 }
 
-func (w Work) CreateShift(ctx context.Context, shift Shift) (Shift, error) {
-	shift.ID = w.uuid()
-	shift.Date = time.Date(
-		shift.Date.Year(), shift.Date.Month(), shift.Date.Day(),
-		0, 0, 0, 0, time.UTC,
-	)
+func (w Work) UpdateWorker(ctx context.Context, worker Worker) (Worker, error) {
+	worker.Skills = NormalizeSkills(worker.Skills)
 	err := w.repo.Transaction(ctx, func(repo Repository) error {
-		// we can rely on foreign key constraint here,
-		// but it'll ties business logic to repository implementation
-		_, err := repo.Worker(ctx, shift.WorkerID)
-		if errors.Is(err, ErrNoRecord) {
-			return fmt.Errorf("worker: %w", ErrNoRecord)
+		before, err := repo.Worker(ctx, worker.ID)
+		if err != nil {
+			return fmt.Errorf("get worker: %w", err)
 		}
+		if err := checkLocation(ctx, repo, worker.LocationID); err != nil {
+			return err
+		}
+		if err := w.checkBookedShifts(ctx, repo, before, worker); err != nil {
+			return err
+		}
+		if err := repo.UpdateWorker(ctx, worker); err != nil {
+			return fmt.Errorf("updating worker: %w", err)
+		}
+		return w.audit(ctx, repo, "worker", "update", worker.ID, before, worker)
+	})
+	if err != nil {
+		return Worker{}, fmt.Errorf("update worker transaction: %w", err)
+	}
+	return worker, nil
+}
+
+func (w Work) PatchWorker(ctx context.Context, id uuid.UUID, patch WorkerPatch) (Worker, error) {
+	var worker Worker
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		var err error
+		worker, err = repo.Worker(ctx, id)
 		if err != nil {
 			return fmt.Errorf("get worker: %w", err)
 		}
+		before := worker
+		if patch.Name != nil {
+			worker.Name = *patch.Name
+		}
+		if patch.Skills != nil {
+			worker.Skills = NormalizeSkills(*patch.Skills)
+		}
+		if patch.LocationID != nil {
+			if err := checkLocation(ctx, repo, patch.LocationID); err != nil {
+				return err
+			}
+			worker.LocationID = patch.LocationID
+		}
+		if err := w.checkBookedShifts(ctx, repo, before, worker); err != nil {
+			return err
+		}
+		if err := repo.UpdateWorker(ctx, worker); err != nil {
+			return fmt.Errorf("updating worker: %w", err)
+		}
+		return w.audit(ctx, repo, "worker", "update", worker.ID, before, worker)
+	})
+	if err != nil {
+		return Worker{}, fmt.Errorf("patch worker transaction: %w", err)
+	}
+	return worker, nil
+}
 
-		shifts, err := repo.Shifts(
-			ctx, ShiftsFilter{WorkerID: &shift.WorkerID, Date: &shift.Date},
-		)
+func (w Work) DeleteWorker(ctx context.Context, id uuid.UUID) error {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		worker, err := repo.Worker(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get worker: %w", err)
+		}
+		// related records are removed explicitly instead of relying on
+		// ON DELETE CASCADE for the same reason as in CreateShift,
+		// every removed record is audited, so it can be restored from the log
+		shifts, err := repo.Shifts(ctx, ShiftsFilter{WorkerID: &id})
+		if err != nil {
+			return fmt.Errorf("list worker shifts: %w", err)
+		}
+		for _, shift := range shifts {
+			if err := w.audit(ctx, repo, "shift", "delete", shift.ID, shift, nil); err != nil {
+				return err
+			}
+		}
+		if err := repo.DeleteShifts(ctx, ShiftsFilter{WorkerID: &id}); err != nil {
+			return fmt.Errorf("deleting worker shifts: %w", err)
+		}
+		patterns, err := repo.Patterns(ctx, PatternsFilter{WorkerID: &id})
+		if err != nil {
+			return fmt.Errorf("list worker patterns: %w", err)
+		}
+		for _, pattern := range patterns {
+			if err := w.audit(ctx, repo, "pattern", "delete", pattern.ID, pattern, nil); err != nil {
+				return err
+			}
+		}
+		if err := repo.DeletePatterns(ctx, PatternsFilter{WorkerID: &id}); err != nil {
+			return fmt.Errorf("deleting worker patterns: %w", err)
+		}
+		availabilities, err := repo.Availabilities(ctx, AvailabilityFilter{WorkerID: &id})
+		if err != nil {
+			return fmt.Errorf("list worker availabilities: %w", err)
+		}
+		for _, availability := range availabilities {
+			if err := w.audit(ctx, repo, "availability", "delete", availability.ID, availability, nil); err != nil {
+				return err
+			}
+		}
+		if err := repo.DeleteAvailabilities(ctx, AvailabilityFilter{WorkerID: &id}); err != nil {
+			return fmt.Errorf("deleting worker availabilities: %w", err)
+		}
+		unavailabilities, err := repo.Unavailabilities(ctx, UnavailabilityFilter{WorkerID: &id})
+		if err != nil {
+			return fmt.Errorf("list worker unavailabilities: %w", err)
+		}
+		for _, unavailability := range unavailabilities {
+			if err := w.audit(ctx, repo, "unavailability", "delete", unavailability.ID, unavailability, nil); err != nil {
+				return err
+			}
+		}
+		if err := repo.DeleteUnavailabilities(ctx, UnavailabilityFilter{WorkerID: &id}); err != nil {
+			return fmt.Errorf("deleting worker unavailabilities: %w", err)
+		}
+		leaves, err := repo.Leaves(ctx, LeavesFilter{WorkerID: &id})
 		if err != nil {
-			return fmt.Errorf("list shifts: %w", err)
+			return fmt.Errorf("list worker leaves: %w", err)
 		}
-		if len(shifts) > 0 {
-			return ErrDayAlreadyBooked
+		for _, leave := range leaves {
+			if err := w.audit(ctx, repo, "leave", "delete", leave.ID, leave, nil); err != nil {
+				return err
+			}
 		}
+		if err := repo.DeleteLeaves(ctx, LeavesFilter{WorkerID: &id}); err != nil {
+			return fmt.Errorf("deleting worker leaves: %w", err)
+		}
+		swaps, err := repo.Swaps(ctx, SwapsFilter{WorkerID: &id})
+		if err != nil {
+			return fmt.Errorf("list worker swaps: %w", err)
+		}
+		for _, swap := range swaps {
+			if err := w.audit(ctx, repo, "swap", "delete", swap.ID, swap, nil); err != nil {
+				return err
+			}
+		}
+		if err := repo.DeleteSwaps(ctx, SwapsFilter{WorkerID: &id}); err != nil {
+			return fmt.Errorf("deleting worker swaps: %w", err)
+		}
+		if err := repo.DeleteWorker(ctx, id); err != nil {
+			return fmt.Errorf("deleting worker: %w", err)
+		}
+		return w.audit(ctx, repo, "worker", "delete", id, worker, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("delete worker transaction: %w", err)
+	}
+	return nil
+}
 
+func (w Work) CreateShift(ctx context.Context, shift Shift) (Shift, error) {
+	shift.ID = w.uuid()
+	shift.Date = TruncateDate(shift.Date)
+	shift.Skills = NormalizeSkills(shift.Skills)
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		if err := w.checkShift(ctx, repo, &shift); err != nil {
+			return err
+		}
 		if err := repo.CreateShift(ctx, shift); err != nil {
 			return fmt.Errorf("creating shift: %w", err)
 		}
-		return nil
+		return w.audit(ctx, repo, "shift", "create", shift.ID, nil, shift)
 	})
 	if err != nil {
 		return Shift{}, fmt.Errorf("create shift transaction: %w", err)
@@ -132,17 +695,334 @@ func (w Work) CreateShift(ctx context.Context, shift Shift) (Shift, error) {
 	return shift, nil
 }
 
-func (w Work) Shifts(ctx context.Context, filter ShiftsFilter) ([]Shift, error) {
+// CreateShifts creates all shifts or none of them. Every shift
+// is checked, including against preceding shifts of the batch,
+// so returned BatchError lists all rejected shifts.
+func (w Work) CreateShifts(ctx context.Context, shifts []Shift) ([]Shift, error) {
+	if len(shifts) == 0 || len(shifts) > MaxBatchSize {
+		return nil, ErrInvalidBatch
+	}
+	created := make([]Shift, 0, len(shifts))
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		batchErr := BatchError{}
+		indices := make(map[uuid.UUID]int, len(shifts))
+		for i, shift := range shifts {
+			shift.ID = w.uuid()
+			shift.Date = TruncateDate(shift.Date)
+			shift.Skills = NormalizeSkills(shift.Skills)
+			err := w.checkShift(ctx, repo, &shift)
+			var planErr Error
+			switch {
+			case errors.As(err, &planErr):
+				item := ItemError{Index: i, Err: err}
+				var conflictErr ConflictError
+				if errors.As(err, &conflictErr) {
+					if j, ok := indices[conflictErr.Shift.ID]; ok {
+						item.ConflictIndex = &j
+					}
+				}
+				batchErr.Items = append(batchErr.Items, item)
+				continue
+			case err != nil:
+				return fmt.Errorf("shift %d: %w", i, err)
+			}
+			// created shift is visible for checks of the next ones
+			if err := repo.CreateShift(ctx, shift); err != nil {
+				return fmt.Errorf("creating shift %d: %w", i, err)
+			}
+			if err := w.audit(ctx, repo, "shift", "create", shift.ID, nil, shift); err != nil {
+				return err
+			}
+			indices[shift.ID] = i
+			created = append(created, shift)
+		}
+		if len(batchErr.Items) > 0 {
+			return batchErr
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create shifts transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+func (w Work) UpdateShift(ctx context.Context, shift Shift) (Shift, error) {
+	shift.Date = TruncateDate(shift.Date)
+	shift.Skills = NormalizeSkills(shift.Skills)
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		before, err := repo.Shift(ctx, shift.ID)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		if err := w.checkShift(ctx, repo, &shift); err != nil {
+			return err
+		}
+		if err := repo.UpdateShift(ctx, shift); err != nil {
+			return fmt.Errorf("updating shift: %w", err)
+		}
+		return w.audit(ctx, repo, "shift", "update", shift.ID, before, shift)
+	})
+	if err != nil {
+		return Shift{}, fmt.Errorf("update shift transaction: %w", err)
+	}
+
+	return shift, nil
+}
+
+func (w Work) PatchShift(ctx context.Context, id uuid.UUID, patch ShiftPatch) (Shift, error) {
+	var shift Shift
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		var err error
+		shift, err = repo.Shift(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		before := shift
+		patch.apply(&shift)
+		// binding tags are checked only for the patch itself,
+		// so relation between start and end has to be validated after merge
+		if shift.End == shift.Start {
+			return ErrInvalidShift
+		}
+		if err := w.checkShift(ctx, repo, &shift); err != nil {
+			return err
+		}
+		if err := repo.UpdateShift(ctx, shift); err != nil {
+			return fmt.Errorf("updating shift: %w", err)
+		}
+		return w.audit(ctx, repo, "shift", "update", shift.ID, before, shift)
+	})
+	if err != nil {
+		return Shift{}, fmt.Errorf("patch shift transaction: %w", err)
+	}
+
+	return shift, nil
+}
+
+func (w Work) DeleteShift(ctx context.Context, id uuid.UUID) error {
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		shift, err := repo.Shift(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		if err := repo.DeleteShift(ctx, id); err != nil {
+			return fmt.Errorf("deleting shift: %w", err)
+		}
+		return w.audit(ctx, repo, "shift", "delete", id, shift, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("delete shift transaction: %w", err)
+	}
+	return nil
+}
+
+func (w Work) Shift(ctx context.Context, id uuid.UUID) (Shift, error) {
+	shift, err := w.repo.Shift(ctx, id)
+	if err != nil {
+		return Shift{}, fmt.Errorf("get shift: %w", err)
+	}
+	return shift, nil
+}
+
+// Shifts returns a page of shifts and a cursor of the next page,
+// the cursor is nil for the last page.
+func (w Work) Shifts(ctx context.Context, filter ShiftsFilter) ([]Shift, *Cursor, error) {
+	switch filter.Sort.Field {
+	case "":
+		filter.Sort.Field = SortByDate
+	case SortByDate, SortByWorker:
+	default:
+		return nil, nil, fmt.Errorf("sort %q: %w", filter.Sort, ErrInvalidFilter)
+	}
+	filter.Limit = pageLimit(filter.Limit)
+	// one extra record is requested to find out if there is a next page
+	filter.Limit++
+
 	if filter.Date != nil {
-		date := time.Date(
-			filter.Date.Year(), filter.Date.Month(), filter.Date.Day(),
-			0, 0, 0, 0, time.UTC,
-		)
+		date := TruncateDate(*filter.Date)
 		filter.Date = &date
 	}
+	if filter.From != nil {
+		from := TruncateDate(*filter.From)
+		filter.From = &from
+	}
+	if filter.To != nil {
+		to := TruncateDate(*filter.To)
+		filter.To = &to
+	}
 	shifts, err := w.repo.Shifts(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("list shift: %w", err)
+		return nil, nil, fmt.Errorf("list shift: %w", err)
+	}
+	if len(shifts) < filter.Limit {
+		return shifts, nil, nil
+	}
+	shifts = shifts[:len(shifts)-1]
+	last := shifts[len(shifts)-1]
+	cursor := &Cursor{Date: last.Date, ID: last.ID}
+	// open shifts are sorted before assigned ones as if worker ID was nil UUID
+	if last.WorkerID != nil {
+		cursor.WorkerID = *last.WorkerID
+	}
+	return shifts, cursor, nil
+}
+
+// ClaimShift assigns worker to the open shift. Shift is claimed before
+// the conflict check, so concurrent claims wait for each other
+// and only one of them succeeds.
+func (w Work) ClaimShift(ctx context.Context, id, workerID uuid.UUID) (Shift, error) {
+	var shift Shift
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		err := repo.ClaimShift(ctx, id, workerID)
+		if errors.Is(err, ErrShiftTaken) {
+			if _, err := repo.Shift(ctx, id); err != nil {
+				return fmt.Errorf("get shift: %w", err)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("claiming shift: %w", err)
+		}
+		shift, err = repo.Shift(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		if err := w.checkShift(ctx, repo, &shift); err != nil {
+			return err
+		}
+		// shift was open until the claim succeeded
+		before := shift
+		before.WorkerID = nil
+		return w.audit(ctx, repo, "shift", "claim", id, before, shift)
+	})
+	if err != nil {
+		return Shift{}, fmt.Errorf("claim shift transaction: %w", err)
+	}
+	return shift, nil
+}
+
+// checkShift verifies that shift location and worker exist, worker is qualified and available,
+// and that shift doesn't conflict with other shifts of the worker according to policy.
+// Shifts of all locations are checked, so worker can't be at two sites at once.
+// Worker of open shift is not checked until the shift is claimed.
+// Compliance rules are checked last, warnings are set to the shift.
+func (w Work) checkShift(ctx context.Context, repo Repository, shift *Shift) error {
+	if err := checkLocation(ctx, repo, shift.LocationID); err != nil {
+		return err
+	}
+	if shift.Open() {
+		return nil
+	}
+	// we can rely on foreign key constraint here,
+	// but it'll ties business logic to repository implementation
+	worker, err := repo.Worker(ctx, *shift.WorkerID)
+	if errors.Is(err, ErrNoRecord) {
+		return fmt.Errorf("worker: %w", ErrNoRecord)
+	}
+	if err != nil {
+		return fmt.Errorf("get worker: %w", err)
+	}
+	if missing := worker.MissingSkills(*shift); len(missing) > 0 {
+		return fmt.Errorf("missing %s: %w", strings.Join(missing, ", "), ErrUnqualified)
+	}
+	if err := w.checkAvailability(ctx, repo, *shift); err != nil {
+		return err
+	}
+
+	days := ConflictDays(w.conflicts)
+	from, to := shift.Date.AddDate(0, 0, -days), shift.Date.AddDate(0, 0, days)
+	shifts, err := repo.Shifts(
+		ctx, ShiftsFilter{WorkerID: shift.WorkerID, From: &from, To: &to},
+	)
+	if err != nil {
+		return fmt.Errorf("list shifts: %w", err)
+	}
+	for _, s := range shifts {
+		if s.ID == shift.ID {
+			continue
+		}
+		err := w.conflicts.Conflict(*shift, s)
+		var reason Error
+		switch {
+		case errors.As(err, &reason):
+			return ConflictError{Shift: s, Reason: reason}
+		case err != nil:
+			return fmt.Errorf("check conflict: %w", err)
+		}
+	}
+	shift.Warnings, err = w.checkCompliance(ctx, repo, *shift)
+	return err
+}
+
+// checkBookedShifts verifies that worker with skills changed from before is still
+// qualified for shifts booked from today on, ConflictError with ErrUnqualified names
+// the first shift worker lacks skills for. Shifts are checked only if skills changed,
+// other worker fields don't restrict shifts.
+func (w Work) checkBookedShifts(ctx context.Context, repo Repository, before, worker Worker) error {
+	if slices.Equal(before.Skills, worker.Skills) {
+		return nil
+	}
+	from := TruncateDate(w.now())
+	shifts, err := repo.Shifts(ctx, ShiftsFilter{WorkerID: &worker.ID, From: &from})
+	if err != nil {
+		return fmt.Errorf("list worker shifts: %w", err)
+	}
+	for _, s := range shifts {
+		if missing := worker.MissingSkills(s); len(missing) > 0 {
+			return fmt.Errorf("missing %s: %w", strings.Join(missing, ", "), ConflictError{Shift: s, Reason: ErrUnqualified})
+		}
+	}
+	return nil
+}
+
+func (p ShiftPatch) apply(shift *Shift) {
+	if p.WorkerID != nil {
+		shift.WorkerID = p.WorkerID
+	}
+	if p.Date != nil {
+		shift.Date = TruncateDate(*p.Date)
+	}
+	switch {
+	case p.Start != nil:
+		shift.Start = *p.Start
+	case p.StartHour != nil:
+		shift.Start = NewClock(*p.StartHour, 0)
+	}
+	switch {
+	case p.End != nil:
+		shift.End = *p.End
+	case p.EndHour != nil:
+		shift.End = NewClock(*p.EndHour, 0)
+	}
+	if p.Skills != nil {
+		shift.Skills = NormalizeSkills(*p.Skills)
+	}
+	if p.LocationID != nil {
+		shift.LocationID = p.LocationID
 	}
-	return shifts, nil
+}
+
+func pageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultLimit
+	case limit > MaxLimit:
+		return MaxLimit
+	default:
+		return limit
+	}
+}
+
+// TruncateDate returns midnight UTC of the date, shift dates are stored truncated.
+func TruncateDate(date time.Time) time.Time {
+	return time.Date(
+		date.Year(), date.Month(), date.Day(),
+		0, 0, 0, 0, time.UTC,
+	)
+}
+
+// WeekStart returns Monday of the date week, Monday is the first day of the week.
+func WeekStart(date time.Time) time.Time {
+	return date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
 }
@@ -2,6 +2,8 @@ package planner_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
 	"testing"
 	"time"
@@ -26,21 +28,63 @@ func genID() uuid.UUID {
 
 type transaction func(planner.Repository) error
 
+// expectAvailable expects availability check of worker that has
+// no availability windows, unavailabilities and approved leaves.
+func expectAvailable(ctx context.Context, repo *repomock.MockRepository, workerID uuid.UUID) *gomock.Call {
+	windows := repo.EXPECT().Availabilities(ctx, planner.AvailabilityFilter{WorkerID: &workerID}).Return(nil, nil)
+	absences := repo.EXPECT().Unavailabilities(ctx, gomock.Any()).Return(nil, nil).After(windows)
+	return repo.EXPECT().Leaves(ctx, gomock.Any()).Return(nil, nil).After(absences)
+}
+
+// auditOf matches audit entry of the entity change, state is checked in TestAudit.
+type auditOf struct {
+	entity, action string
+	id             uuid.UUID
+}
+
+func (m auditOf) Matches(x any) bool {
+	entry, ok := x.(planner.AuditEntry)
+	return ok && entry.Entity == m.entity && entry.Action == m.action && entry.EntityID == m.id
+}
+
+func (m auditOf) String() string {
+	return fmt.Sprintf("audit entry of %s %s %s", m.entity, m.action, m.id)
+}
+
+// expectAudit expects audit entry of the entity change.
+func expectAudit(ctx context.Context, repo *repomock.MockRepository, entity, action string, id uuid.UUID) *gomock.Call {
+	return repo.EXPECT().CreateAuditEntry(ctx, auditOf{entity: entity, action: action, id: id}).Return(nil)
+}
+
 func TestCreateWorker(t *testing.T) {
 	t.Parallel()
 
+	locationID := uuid.New()
 	tests := []struct {
-		name    string
-		input   planner.Worker
-		want    planner.Worker
-		repoErr error
-		expErr  error
+		name        string
+		input       planner.Worker
+		want        planner.Worker
+		locationErr error
+		repoErr     error
+		expErr      error
 	}{
 		{
 			name:  "Success",
 			input: planner.Worker{Name: "Buddy Guy"},
 			want:  planner.Worker{ID: fixedID, Name: "Buddy Guy"},
 		},
+		{
+			name:  "With location",
+			input: planner.Worker{Name: "Buddy Guy", LocationID: &locationID},
+			want:  planner.Worker{ID: fixedID, Name: "Buddy Guy", LocationID: &locationID},
+		},
+		{
+			name:        "Unknown location",
+			input:       planner.Worker{Name: "Buddy Guy", LocationID: &locationID},
+			want:        planner.Worker{},
+			locationErr: planner.ErrNoRecord,
+			expErr:      planner.ErrNoRecord,
+		},
 		{
 			name:    "Repo error",
 			input:   planner.Worker{Name: "Buddy Guy"},
@@ -62,7 +106,16 @@ func TestCreateWorker(t *testing.T) {
 			plan := planner.New(repo, planner.UUIDGenerator(genID))
 			expected := tt.input
 			expected.ID = fixedID
-			repo.EXPECT().CreateWorker(ctx, expected).Return(tt.repoErr)
+			repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) })
+			if tt.input.LocationID != nil {
+				repo.EXPECT().Location(ctx, *tt.input.LocationID).Return(planner.Location{ID: *tt.input.LocationID}, tt.locationErr)
+			}
+			if tt.locationErr == nil {
+				repo.EXPECT().CreateWorker(ctx, expected).Return(tt.repoErr)
+			}
+			if tt.locationErr == nil && tt.repoErr == nil {
+				expectAudit(ctx, repo, "worker", "create", fixedID)
+			}
 
 			result, err := plan.CreateWorker(ctx, tt.input)
 			if tt.expErr == nil {
@@ -109,9 +162,12 @@ func TestWorkers(t *testing.T) {
 			repo := repomock.NewMockRepository(ctrl)
 
 			plan := planner.New(repo, planner.UUIDGenerator(genID))
-			repo.EXPECT().Workers(ctx, tt.input).Return(tt.want, tt.repoErr)
+			expected := tt.input
+			expected.Sort = planner.Sort{Field: planner.SortByName}
+			expected.Limit = planner.DefaultLimit + 1
+			repo.EXPECT().Workers(ctx, expected).Return(tt.want, tt.repoErr)
 
-			result, err := plan.Workers(ctx, tt.input)
+			result, _, err := plan.Workers(ctx, tt.input)
 			if tt.expErr == nil {
 				assert.NoError(t, err)
 				assert.Equal(t, result, tt.want)
@@ -127,6 +183,7 @@ func TestCreateShift(t *testing.T) {
 	t.Parallel()
 	id1 := uuid.New()
 	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
 
 	tests := []struct {
 		name         string
@@ -142,35 +199,56 @@ func TestCreateShift(t *testing.T) {
 	}{
 		{
 			name:         "Success",
-			input:        planner.Shift{WorkerID: id1, Date: date, StartHour: 8, EndHour: 16},
+			input:        planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
 			worker:       planner.Worker{ID: id1, Name: "Buddy Guy"},
-			want:         planner.Shift{ID: fixedID, WorkerID: id1, Date: date, StartHour: 8, EndHour: 16},
+			want:         planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
 			workerShifts: nil,
 		},
 		{
 			name:         "Day booked",
-			input:        planner.Shift{WorkerID: id1, Date: date, StartHour: 8, EndHour: 16},
+			input:        planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
 			worker:       planner.Worker{ID: id1, Name: "Buddy Guy"},
-			workerShifts: []planner.Shift{{WorkerID: id1, Date: date, StartHour: 0, EndHour: 8}},
+			workerShifts: []planner.Shift{{WorkerID: &id1, Date: date, Start: planner.NewClock(0, 0), End: planner.NewClock(8, 0)}},
 			expErr:       planner.ErrDayAlreadyBooked,
 		},
+		{
+			name:         "Overnight shift",
+			input:        planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)},
+			worker:       planner.Worker{ID: id1, Name: "Buddy Guy"},
+			workerShifts: []planner.Shift{{WorkerID: &id1, Date: nextDay, Start: planner.NewClock(6, 0), End: planner.NewClock(14, 0)}},
+			want:         planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)},
+		},
+		{
+			name:         "Overlaps with next day shift",
+			input:        planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)},
+			worker:       planner.Worker{ID: id1, Name: "Buddy Guy"},
+			workerShifts: []planner.Shift{{WorkerID: &id1, Date: nextDay, Start: planner.NewClock(5, 0), End: planner.NewClock(13, 0)}},
+			expErr:       planner.ErrShiftsOverlap,
+		},
+		{
+			name:         "Overlaps with previous day overnight shift",
+			input:        planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(5, 30), End: planner.NewClock(13, 0)},
+			worker:       planner.Worker{ID: id1, Name: "Buddy Guy"},
+			workerShifts: []planner.Shift{{WorkerID: &id1, Date: prevDay, Start: planner.NewClock(22, 0), End: planner.NewClock(6, 0)}},
+			expErr:       planner.ErrShiftsOverlap,
+		},
 		{
 			name:          "No such worker",
-			input:         planner.Shift{WorkerID: id1, Date: date, StartHour: 8, EndHour: 16},
+			input:         planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
 			worker:        planner.Worker{},
 			repoWorkerErr: planner.ErrNoRecord,
 			expErr:        planner.ErrNoRecord,
 		},
 		{
 			name:          "Shifts repo err",
-			input:         planner.Shift{WorkerID: id1, Date: date, StartHour: 8, EndHour: 16},
+			input:         planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
 			worker:        planner.Worker{ID: id1, Name: "Buddy Guy"},
 			repoShiftsErr: net.UnknownNetworkError("error"),
 			expErr:        net.UnknownNetworkError("error"),
 		},
 		{
 			name:           "Create shift repo err",
-			input:          planner.Shift{WorkerID: id1, Date: date, StartHour: 8, EndHour: 16},
+			input:          planner.Shift{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
 			worker:         planner.Worker{ID: id1, Name: "Buddy Guy"},
 			repoCreaterErr: net.UnknownNetworkError("error"),
 			expErr:         net.UnknownNetworkError("error"),
@@ -194,15 +272,19 @@ func TestCreateShift(t *testing.T) {
 			if tt.repoWorkerErr == nil {
 				expectations = append(
 					expectations,
-					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, Date: &date}).Return(tt.workerShifts, tt.repoShiftsErr),
+					expectAvailable(ctx, repo, id1),
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &prevDay, To: &nextDay}).Return(tt.workerShifts, tt.repoShiftsErr),
 				)
-				if tt.repoShiftsErr == nil && len(tt.workerShifts) == 0 {
+				if tt.repoShiftsErr == nil && tt.expErr != planner.ErrDayAlreadyBooked && tt.expErr != planner.ErrShiftsOverlap {
 					expected := tt.input
 					expected.ID = fixedID
 					expectations = append(
 						expectations,
 						repo.EXPECT().CreateShift(ctx, expected).Return(tt.repoCreaterErr),
 					)
+					if tt.repoCreaterErr == nil {
+						expectations = append(expectations, expectAudit(ctx, repo, "shift", "create", fixedID))
+					}
 				}
 			}
 			gomock.InOrder(expectations...)
@@ -234,7 +316,7 @@ func TestShifts(t *testing.T) {
 		{
 			name:  "Success",
 			input: planner.ShiftsFilter{WorkerID: &fixedID, Date: &date},
-			want:  []planner.Shift{{ID: id1, Date: dateTrunc, StartHour: 8, EndHour: 16}},
+			want:  []planner.Shift{{ID: id1, Date: dateTrunc, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}},
 		},
 		{
 			name:    "Repo error",
@@ -255,17 +337,694 @@ func TestShifts(t *testing.T) {
 			repo := repomock.NewMockRepository(ctrl)
 
 			plan := planner.New(repo, planner.UUIDGenerator(genID))
-			tt.input.Date = &dateTrunc
-			repo.EXPECT().Shifts(ctx, tt.input).Return(tt.want, tt.repoErr)
+			expected := tt.input
+			expected.Date = &dateTrunc
+			expected.Sort = planner.Sort{Field: planner.SortByDate}
+			expected.Limit = planner.DefaultLimit + 1
+			repo.EXPECT().Shifts(ctx, expected).Return(tt.want, tt.repoErr)
+
+			result, _, err := plan.Shifts(ctx, tt.input)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, result, tt.want)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+
+		})
+	}
+}
+
+func TestDeleteWorker(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.New()
+	shift := planner.Shift{ID: uuid.New(), WorkerID: &id1}
+	pattern := planner.ShiftPattern{ID: uuid.New(), WorkerID: id1}
+	availability := planner.Availability{ID: uuid.New(), WorkerID: id1}
+	unavailability := planner.Unavailability{ID: uuid.New(), WorkerID: id1}
+	leave := planner.LeaveRequest{ID: uuid.New(), WorkerID: id1}
+	swap := planner.SwapRequest{ID: uuid.New(), ShiftID: shift.ID, FromWorkerID: id1}
+
+	tests := []struct {
+		name          string
+		repoWorkerErr error
+		repoShiftsErr error
+		repoDeleteErr error
+		expErr        error
+		deleteCalled  bool
+	}{
+		{
+			name:         "Success",
+			deleteCalled: true,
+		},
+		{
+			name:          "No such worker",
+			repoWorkerErr: planner.ErrNoRecord,
+			expErr:        planner.ErrNoRecord,
+		},
+		{
+			name:          "Delete worker repo err",
+			repoDeleteErr: net.UnknownNetworkError("error"),
+			expErr:        net.UnknownNetworkError("error"),
+			deleteCalled:  true,
+		},
+		{
+			name:          "Delete shifts repo err",
+			repoShiftsErr: net.UnknownNetworkError("error"),
+			expErr:        net.UnknownNetworkError("error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			plan := planner.New(repo, planner.UUIDGenerator(genID))
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, tt.repoWorkerErr),
+			}
+			if tt.repoWorkerErr == nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1}).Return([]planner.Shift{shift}, nil),
+					expectAudit(ctx, repo, "shift", "delete", shift.ID),
+					repo.EXPECT().DeleteShifts(ctx, planner.ShiftsFilter{WorkerID: &id1}).Return(tt.repoShiftsErr),
+				)
+			}
+			if tt.deleteCalled {
+				expectations = append(
+					expectations,
+					// every cascade deleted record is audited
+					repo.EXPECT().Patterns(ctx, planner.PatternsFilter{WorkerID: &id1}).Return([]planner.ShiftPattern{pattern}, nil),
+					expectAudit(ctx, repo, "pattern", "delete", pattern.ID),
+					repo.EXPECT().DeletePatterns(ctx, planner.PatternsFilter{WorkerID: &id1}).Return(nil),
+					repo.EXPECT().Availabilities(ctx, planner.AvailabilityFilter{WorkerID: &id1}).Return([]planner.Availability{availability}, nil),
+					expectAudit(ctx, repo, "availability", "delete", availability.ID),
+					repo.EXPECT().DeleteAvailabilities(ctx, planner.AvailabilityFilter{WorkerID: &id1}).Return(nil),
+					repo.EXPECT().Unavailabilities(ctx, planner.UnavailabilityFilter{WorkerID: &id1}).Return([]planner.Unavailability{unavailability}, nil),
+					expectAudit(ctx, repo, "unavailability", "delete", unavailability.ID),
+					repo.EXPECT().DeleteUnavailabilities(ctx, planner.UnavailabilityFilter{WorkerID: &id1}).Return(nil),
+					repo.EXPECT().Leaves(ctx, planner.LeavesFilter{WorkerID: &id1}).Return([]planner.LeaveRequest{leave}, nil),
+					expectAudit(ctx, repo, "leave", "delete", leave.ID),
+					repo.EXPECT().DeleteLeaves(ctx, planner.LeavesFilter{WorkerID: &id1}).Return(nil),
+					repo.EXPECT().Swaps(ctx, planner.SwapsFilter{WorkerID: &id1}).Return([]planner.SwapRequest{swap}, nil),
+					expectAudit(ctx, repo, "swap", "delete", swap.ID),
+					repo.EXPECT().DeleteSwaps(ctx, planner.SwapsFilter{WorkerID: &id1}).Return(nil),
+					repo.EXPECT().DeleteWorker(ctx, id1).Return(tt.repoDeleteErr),
+				)
+			}
+			if tt.expErr == nil {
+				expectations = append(expectations, expectAudit(ctx, repo, "worker", "delete", id1))
+			}
+			gomock.InOrder(expectations...)
+
+			err := plan.DeleteWorker(ctx, id1)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestUpdateShift(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name         string
+		input        planner.Shift
+		existingErr  error
+		workerShifts []planner.Shift
+		repoUpdErr   error
+		expErr       error
+	}{
+		{
+			name:         "Success",
+			input:        planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+			workerShifts: []planner.Shift{{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(0, 0), End: planner.NewClock(8, 0)}},
+		},
+		{
+			name:         "Day booked by another shift",
+			input:        planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+			workerShifts: []planner.Shift{{ID: uuid.New(), WorkerID: &id1, Date: date, Start: planner.NewClock(0, 0), End: planner.NewClock(8, 0)}},
+			expErr:       planner.ErrDayAlreadyBooked,
+		},
+		{
+			name:        "No such shift",
+			input:       planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+			existingErr: planner.ErrNoRecord,
+			expErr:      planner.ErrNoRecord,
+		},
+		{
+			name:       "Update repo err",
+			input:      planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+			repoUpdErr: net.UnknownNetworkError("error"),
+			expErr:     net.UnknownNetworkError("error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			plan := planner.New(repo, planner.UUIDGenerator(genID))
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Shift(ctx, fixedID).Return(tt.input, tt.existingErr),
+			}
+			if tt.existingErr == nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+					expectAvailable(ctx, repo, id1),
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &prevDay, To: &nextDay}).Return(tt.workerShifts, nil),
+				)
+				if !errors.Is(tt.expErr, planner.ErrDayAlreadyBooked) {
+					expectations = append(expectations, repo.EXPECT().UpdateShift(ctx, tt.input).Return(tt.repoUpdErr))
+				}
+				if tt.expErr == nil {
+					expectations = append(expectations, expectAudit(ctx, repo, "shift", "update", fixedID))
+				}
+			}
+			gomock.InOrder(expectations...)
+
+			result, err := plan.UpdateShift(ctx, tt.input)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, result, tt.input)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestPatchShift(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+	existing := planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+
+	tests := []struct {
+		name   string
+		patch  planner.ShiftPatch
+		want   planner.Shift
+		expErr error
+	}{
+		{
+			name:  "Success",
+			patch: planner.ShiftPatch{EndHour: ptr(20)},
+			want:  planner.Shift{ID: fixedID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(20, 0)},
+		},
+		{
+			name:   "Start equals end after merge",
+			patch:  planner.ShiftPatch{StartHour: ptr(16)},
+			expErr: planner.ErrInvalidShift,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			plan := planner.New(repo, planner.UUIDGenerator(genID))
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Shift(ctx, fixedID).Return(existing, nil),
+			}
+			if tt.expErr == nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+					expectAvailable(ctx, repo, id1),
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &prevDay, To: &nextDay}).Return(nil, nil),
+					repo.EXPECT().UpdateShift(ctx, tt.want).Return(nil),
+					expectAudit(ctx, repo, "shift", "update", fixedID),
+				)
+			}
+			gomock.InOrder(expectations...)
+
+			result, err := plan.PatchShift(ctx, fixedID, tt.patch)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, result, tt.want)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestWorker(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		want    planner.Worker
+		repoErr error
+		expErr  error
+	}{
+		{
+			name: "Success",
+			want: planner.Worker{ID: fixedID, Name: "Buddy Guy"},
+		},
+		{
+			name:    "No such worker",
+			repoErr: planner.ErrNoRecord,
+			expErr:  planner.ErrNoRecord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			plan := planner.New(repo, planner.UUIDGenerator(genID))
+			repo.EXPECT().Worker(ctx, fixedID).Return(tt.want, tt.repoErr)
+
+			result, err := plan.Worker(ctx, fixedID)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, result, tt.want)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestShift(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		want    planner.Shift
+		repoErr error
+		expErr  error
+	}{
+		{
+			name: "Success",
+			want: planner.Shift{ID: fixedID, WorkerID: &fixedID, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+		},
+		{
+			name:    "No such shift",
+			repoErr: planner.ErrNoRecord,
+			expErr:  planner.ErrNoRecord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			plan := planner.New(repo, planner.UUIDGenerator(genID))
+			repo.EXPECT().Shift(ctx, fixedID).Return(tt.want, tt.repoErr)
 
-			result, err := plan.Shifts(ctx, tt.input)
+			result, err := plan.Shift(ctx, fixedID)
 			if tt.expErr == nil {
 				assert.NoError(t, err)
 				assert.Equal(t, result, tt.want)
 			} else {
 				assert.ErrorIs(t, err, tt.expErr)
 			}
+		})
+	}
+}
+
+func TestShiftsRange(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	from := time.Date(2025, 11, 3, 14, 22, 15, 0, time.UTC)
+	to := time.Date(2025, 11, 9, 23, 59, 0, 0, time.UTC)
+	fromTrunc := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	toTrunc := time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC)
+	want := []planner.Shift{
+		{ID: uuid.New(), WorkerID: &fixedID, Date: fromTrunc, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+		{ID: uuid.New(), WorkerID: &fixedID, Date: toTrunc, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	plan := planner.New(repo, planner.UUIDGenerator(genID))
+	repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{
+		From: &fromTrunc, To: &toTrunc,
+		Sort: planner.Sort{Field: planner.SortByDate}, Limit: planner.DefaultLimit + 1,
+	}).Return(want, nil)
+
+	result, _, err := plan.Shifts(ctx, planner.ShiftsFilter{From: &from, To: &to})
+	assert.NoError(t, err)
+	assert.Equal(t, result, want)
+}
+
+func TestWorkersPaging(t *testing.T) {
+	t.Parallel()
+	id1, id2, id3 := uuid.New(), uuid.New(), uuid.New()
+	cursor := planner.Cursor{Name: "Buddy", ID: id1}
+
+	tests := []struct {
+		name     string
+		input    planner.WorkersFilter
+		repoCall *planner.WorkersFilter
+		repoResp []planner.Worker
+		want     []planner.Worker
+		wantNext *planner.Cursor
+		expErr   error
+	}{
+		{
+			name:     "Next page exists",
+			input:    planner.WorkersFilter{Limit: 2, Sort: planner.Sort{Field: planner.SortByID, Desc: true}},
+			repoCall: &planner.WorkersFilter{Limit: 3, Sort: planner.Sort{Field: planner.SortByID, Desc: true}},
+			repoResp: []planner.Worker{{ID: id1, Name: "A"}, {ID: id2, Name: "B"}, {ID: id3, Name: "C"}},
+			want:     []planner.Worker{{ID: id1, Name: "A"}, {ID: id2, Name: "B"}},
+			wantNext: &planner.Cursor{Name: "B", ID: id2},
+		},
+		{
+			name:     "Last page",
+			input:    planner.WorkersFilter{Limit: 2, After: &cursor},
+			repoCall: &planner.WorkersFilter{Limit: 3, After: &cursor, Sort: planner.Sort{Field: planner.SortByName}},
+			repoResp: []planner.Worker{{ID: id3, Name: "C"}},
+			want:     []planner.Worker{{ID: id3, Name: "C"}},
+		},
+		{
+			name:     "Limit capped",
+			input:    planner.WorkersFilter{Limit: planner.MaxLimit * 2},
+			repoCall: &planner.WorkersFilter{Limit: planner.MaxLimit + 1, Sort: planner.Sort{Field: planner.SortByName}},
+		},
+		{
+			name:   "Unknown sort",
+			input:  planner.WorkersFilter{Sort: planner.ParseSort("-date")},
+			expErr: planner.ErrInvalidFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
 
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			plan := planner.New(repo, planner.UUIDGenerator(genID))
+			if tt.repoCall != nil {
+				repo.EXPECT().Workers(ctx, *tt.repoCall).Return(tt.repoResp, nil)
+			}
+
+			result, next, err := plan.Workers(ctx, tt.input)
+			if tt.expErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+				assert.Equal(t, tt.wantNext, next)
+			} else {
+				assert.ErrorIs(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+	cursor := planner.Cursor{
+		Date:     time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC),
+		WorkerID: uuid.New(),
+		ID:       uuid.New(),
+	}
+
+	parsed, err := planner.ParseCursor(cursor.String())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	_, err = planner.ParseCursor("not a cursor")
+	assert.ErrorIs(t, err, planner.ErrInvalidFilter)
+}
+
+func TestCreateShifts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1, id2 := uuid.New(), uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+	booked := planner.Shift{ID: uuid.New(), WorkerID: &id2, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	batch := []planner.Shift{
+		{WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+		{WorkerID: &id2, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)},
+		{WorkerID: &id1, Date: date, Start: planner.NewClock(18, 0), End: planner.NewClock(20, 0)},
+	}
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	next := 0
+	seqID := func() uuid.UUID {
+		next++
+		return ids[next-1]
+	}
+	first := batch[0]
+	first.ID = ids[0]
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	plan := planner.New(repo, planner.UUIDGenerator(seqID))
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		expectAvailable(ctx, repo, id1),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &prevDay, To: &nextDay}).Return(nil, nil),
+		repo.EXPECT().CreateShift(ctx, first).Return(nil),
+		expectAudit(ctx, repo, "shift", "create", ids[0]),
+		repo.EXPECT().Worker(ctx, id2).Return(planner.Worker{ID: id2}, nil),
+		expectAvailable(ctx, repo, id2),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id2, From: &prevDay, To: &nextDay}).Return([]planner.Shift{booked}, nil),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		expectAvailable(ctx, repo, id1),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &prevDay, To: &nextDay}).Return([]planner.Shift{first}, nil),
+	)
+
+	_, err := plan.CreateShifts(ctx, batch)
+	var batchErr planner.BatchError
+	assert.ErrorAs(t, err, &batchErr)
+	assert.Len(t, batchErr.Items, 2)
+	assert.Equal(t, 1, batchErr.Items[0].Index)
+	assert.ErrorIs(t, batchErr.Items[0].Err, planner.ErrDayAlreadyBooked)
+	assert.Nil(t, batchErr.Items[0].ConflictIndex)
+	assert.Equal(t, 2, batchErr.Items[1].Index)
+	assert.ErrorIs(t, batchErr.Items[1].Err, planner.ErrDayAlreadyBooked)
+	assert.Equal(t, ptr(0), batchErr.Items[1].ConflictIndex)
+
+	_, err = plan.CreateShifts(ctx, nil)
+	assert.ErrorIs(t, err, planner.ErrInvalidBatch)
+}
+
+func TestCreateWorkers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	locationID, missingID := uuid.New(), uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	next := 0
+	seqID := func() uuid.UUID {
+		next++
+		return ids[next-1]
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	plan := planner.New(repo, planner.UUIDGenerator(seqID))
+
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().CreateWorker(ctx, planner.Worker{ID: ids[0], Name: "Ann", Skills: []string{"forklift"}}).Return(nil),
+		expectAudit(ctx, repo, "worker", "create", ids[0]),
+		repo.EXPECT().Location(ctx, locationID).Return(planner.Location{ID: locationID}, nil),
+		repo.EXPECT().CreateWorker(ctx, planner.Worker{ID: ids[1], Name: "Bob", LocationID: &locationID}).Return(nil),
+		expectAudit(ctx, repo, "worker", "create", ids[1]),
+		repo.EXPECT().Location(ctx, missingID).Return(planner.Location{}, planner.ErrNoRecord),
+	)
+	_, err := plan.CreateWorkers(ctx, []planner.Worker{
+		{Name: "Ann", Skills: []string{" Forklift "}},
+		{Name: "Bob", LocationID: &locationID},
+		{Name: "Eve", LocationID: &missingID},
+	})
+	var batchErr planner.BatchError
+	assert.ErrorAs(t, err, &batchErr)
+	assert.Len(t, batchErr.Items, 1)
+	assert.Equal(t, 2, batchErr.Items[0].Index)
+	assert.ErrorIs(t, batchErr.Items[0].Err, planner.ErrNoRecord)
+
+	_, err = plan.CreateWorkers(ctx, make([]planner.Worker, planner.MaxBatchSize+1))
+	assert.ErrorIs(t, err, planner.ErrInvalidBatch)
+}
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	plan := planner.New(repo, planner.UUIDGenerator(func() uuid.UUID { return id })).DryRun()
+
+	worker := planner.Worker{ID: id, Name: "Ann"}
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error {
+			err := f(repo)
+			// successful changes are rolled back with an error too
+			assert.Error(t, err)
+			return err
+		}),
+		repo.EXPECT().CreateWorker(ctx, worker).Return(nil),
+		expectAudit(ctx, repo, "worker", "create", id),
+	)
+	created, err := plan.CreateWorkers(ctx, []planner.Worker{{Name: "Ann"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []planner.Worker{worker}, created)
+
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().CreateWorker(ctx, worker).Return(planner.ErrNoRecord),
+	)
+	_, err = plan.CreateWorkers(ctx, []planner.Worker{{Name: "Ann"}})
+	assert.ErrorIs(t, err, planner.ErrNoRecord)
+}
+
+func TestClaimShift(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.New()
+	shiftID := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+	claimed := planner.Shift{ID: shiftID, WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	taken := claimed
+	other := uuid.New()
+	taken.WorkerID = &other
+
+	tests := []struct {
+		name         string
+		claimErr     error
+		current      planner.Shift
+		currentErr   error
+		workerShifts []planner.Shift
+		expErr       error
+	}{
+		{name: "Success", current: claimed},
+		{name: "Already taken", claimErr: planner.ErrShiftTaken, current: taken, expErr: planner.ErrShiftTaken},
+		{name: "No such shift", claimErr: planner.ErrShiftTaken, currentErr: planner.ErrNoRecord, expErr: planner.ErrNoRecord},
+		{
+			name:         "Conflicts with worker shift",
+			current:      claimed,
+			workerShifts: []planner.Shift{{ID: uuid.New(), WorkerID: &id1, Date: date, Start: planner.NewClock(18, 0), End: planner.NewClock(22, 0)}},
+			expErr:       planner.ErrDayAlreadyBooked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().ClaimShift(ctx, shiftID, id1).Return(tt.claimErr),
+				repo.EXPECT().Shift(ctx, shiftID).Return(tt.current, tt.currentErr),
+			}
+			if tt.claimErr == nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+					expectAvailable(ctx, repo, id1),
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &prevDay, To: &nextDay}).Return(tt.workerShifts, nil),
+				)
+			}
+			if tt.expErr == nil {
+				expectations = append(expectations, expectAudit(ctx, repo, "shift", "claim", shiftID))
+			}
+			gomock.InOrder(expectations...)
+
+			plan := planner.New(repo)
+			result, err := plan.ClaimShift(ctx, shiftID, id1)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, claimed, result)
+		})
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	t.Parallel()
+	locationID, missingID := uuid.New(), uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		from, to   time.Time
+		locationID *uuid.UUID
+		expErr     error
+	}{
+		{name: "Same day", from: date.Add(8 * time.Hour), to: date.Add(16 * time.Hour)},
+		{name: "Location", from: date, to: date.AddDate(0, 0, 6), locationID: &locationID},
+		{name: "Reversed", from: date.AddDate(0, 0, 1), to: date, expErr: planner.ErrInvalidRange},
+		{name: "Too long", from: date, to: date.AddDate(0, 0, planner.MaxRangeDays), expErr: planner.ErrInvalidRange},
+		{name: "Unknown location", from: date, to: date, locationID: &missingID, expErr: planner.ErrNoRecord},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+			repo.EXPECT().Location(ctx, locationID).Return(planner.Location{ID: locationID}, nil).AnyTimes()
+			repo.EXPECT().Location(ctx, missingID).Return(planner.Location{}, planner.ErrNoRecord).AnyTimes()
+
+			from, to, err := planner.CheckRange(ctx, repo, tt.from, tt.to, tt.locationID)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.from.Truncate(24*time.Hour), from)
+			assert.Equal(t, tt.to.Truncate(24*time.Hour), to)
 		})
 	}
 }
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+// Target is minimum headcount for hours of the weekdays.
+type Target struct {
+	Weekdays planner.Weekdays
+	// Start and End are bounds of hours, End is exclusive.
+	Start, End int
+	Headcount  int
+}
+
+type CoverageFilter struct {
+	// LocationID limits coverage to shifts of the location.
+	LocationID *uuid.UUID `json:"location_id"`
+	// Skill limits coverage to workers having the skill.
+	Skill string `json:"skill"`
+	// From and To are inclusive range of dates.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Slot is an hour with number of workers on shift during it.
+type Slot struct {
+	Start     time.Time `json:"start"`
+	Headcount int       `json:"headcount"`
+	Target    int       `json:"target"`
+	// Missing is a number of workers needed to reach the target.
+	Missing int `json:"missing"`
+}
+
+// ParseTargets parses "[days/]HH-HH:headcount" specs, days are a weekday
+// or a range of them, e.g. "mon-fri/08-18:3", "sat/10-16:1" or "00-24:1" for every day.
+func ParseTargets(specs []string) ([]Target, error) {
+	targets := make([]Target, 0, len(specs))
+	for _, spec := range specs {
+		target, err := parseTarget(spec)
+		if err != nil {
+			return nil, fmt.Errorf("staffing target %q: %w", spec, err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func parseTarget(spec string) (Target, error) {
+	target := Target{Weekdays: planner.NewWeekdays(0, 1, 2, 3, 4, 5, 6)}
+	if days, rest, ok := strings.Cut(spec, "/"); ok {
+		weekdays, err := parseDays(days)
+		if err != nil {
+			return Target{}, err
+		}
+		target.Weekdays, spec = weekdays, rest
+	}
+	hours, headcount, ok := strings.Cut(spec, ":")
+	if !ok {
+		return Target{}, fmt.Errorf("expected HH-HH:headcount")
+	}
+	start, end, ok := strings.Cut(hours, "-")
+	if !ok {
+		return Target{}, fmt.Errorf("expected HH-HH hours")
+	}
+	var err error
+	if target.Start, err = strconv.Atoi(start); err != nil {
+		return Target{}, fmt.Errorf("start hour: %w", err)
+	}
+	if target.End, err = strconv.Atoi(end); err != nil {
+		return Target{}, fmt.Errorf("end hour: %w", err)
+	}
+	if target.Start < 0 || target.End > 24 || target.Start >= target.End {
+		return Target{}, fmt.Errorf("hours must be within 00-24 with start before end")
+	}
+	if target.Headcount, err = strconv.Atoi(headcount); err != nil || target.Headcount < 1 {
+		return Target{}, fmt.Errorf("headcount must be a positive number")
+	}
+	return target, nil
+}
+
+// parseDays parses a weekday or an inclusive range of weekdays,
+// range can wrap around the week, e.g. fri-mon.
+func parseDays(days string) (planner.Weekdays, error) {
+	first, last, isRange := strings.Cut(days, "-")
+	from, err := planner.ParseWeekday(first)
+	if err != nil {
+		return 0, err
+	}
+	to := from
+	if isRange {
+		if to, err = planner.ParseWeekday(last); err != nil {
+			return 0, err
+		}
+	}
+	var weekdays planner.Weekdays
+	for d := from; ; d = (d + 1) % 7 {
+		weekdays |= planner.NewWeekdays(d)
+		if d == to {
+			return weekdays, nil
+		}
+	}
+}
+
+// Coverage counts workers on shift for every hour of the date range.
+// Shift covers hours it overlaps with, so 08:30-16:30 shift covers 08 to 16.
+func (r Reporter) Coverage(ctx context.Context, filter CoverageFilter) ([]Slot, error) {
+	from, to, err := planner.CheckRange(ctx, r.repo, filter.From, filter.To, filter.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	var skilled map[uuid.UUID]bool
+	if skills := planner.NormalizeSkills([]string{filter.Skill}); len(skills) > 0 {
+		workers, err := r.repo.Workers(ctx, planner.WorkersFilter{Skills: skills})
+		if err != nil {
+			return nil, fmt.Errorf("list workers: %w", err)
+		}
+		skilled = map[uuid.UUID]bool{}
+		for _, w := range workers {
+			skilled[w.ID] = true
+		}
+	}
+
+	// overnight shifts of the previous day cover first hours of the range
+	loadFrom := from.AddDate(0, 0, -1)
+	shifts, err := r.repo.Shifts(ctx, planner.ShiftsFilter{LocationID: filter.LocationID, From: &loadFrom, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("list shifts: %w", err)
+	}
+
+	end := to.AddDate(0, 0, 1)
+	// worker can have several shifts in the same hour, they are counted once
+	workers := make([]map[uuid.UUID]bool, int(end.Sub(from)/time.Hour))
+	for _, s := range shifts {
+		if s.Open() || (skilled != nil && !skilled[*s.WorkerID]) {
+			continue
+		}
+		start := s.StartTime().Truncate(time.Hour)
+		if start.Before(from) {
+			start = from
+		}
+		for slot := start; slot.Before(s.EndTime()) && slot.Before(end); slot = slot.Add(time.Hour) {
+			i := int(slot.Sub(from) / time.Hour)
+			if workers[i] == nil {
+				workers[i] = map[uuid.UUID]bool{}
+			}
+			workers[i][*s.WorkerID] = true
+		}
+	}
+
+	slots := make([]Slot, len(workers))
+	for i := range slots {
+		start := from.Add(time.Duration(i) * time.Hour)
+		slots[i] = Slot{Start: start, Headcount: len(workers[i]), Target: r.target(start)}
+		slots[i].Missing = max(slots[i].Target-slots[i].Headcount, 0)
+	}
+	return slots, nil
+}
+
+// target returns the highest headcount targeted for the hour.
+func (r Reporter) target(slot time.Time) int {
+	headcount := 0
+	for _, t := range r.targets {
+		if t.Weekdays.Has(slot.Weekday()) && slot.Hour() >= t.Start && slot.Hour() < t.End {
+			headcount = max(headcount, t.Headcount)
+		}
+	}
+	return headcount
+}
@@ -0,0 +1,135 @@
+package report_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/report"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestParseTargets(t *testing.T) {
+	t.Parallel()
+	targets, err := report.ParseTargets([]string{"mon-fri/08-18:3", "SAT/10-16:1", "fri-mon/00-24:1", "06-22:2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []report.Target{
+		{Weekdays: planner.NewWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday), Start: 8, End: 18, Headcount: 3},
+		{Weekdays: planner.NewWeekdays(time.Saturday), Start: 10, End: 16, Headcount: 1},
+		{Weekdays: planner.NewWeekdays(time.Friday, time.Saturday, time.Sunday, time.Monday), Start: 0, End: 24, Headcount: 1},
+		{Weekdays: planner.NewWeekdays(0, 1, 2, 3, 4, 5, 6), Start: 6, End: 22, Headcount: 2},
+	}, targets)
+
+	for _, spec := range []string{"08-18", "08:3", "18-08:3", "08-25:3", "08-18:0", "mon-fry/08-18:3", "mon/8am-18:3"} {
+		_, err := report.ParseTargets([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	t.Parallel()
+	w1, w2, w3 := uuid.New(), uuid.New(), uuid.New()
+	// Monday
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay := date.AddDate(0, 0, -1)
+	at := func(hour int) time.Time { return date.Add(time.Duration(hour) * time.Hour) }
+	shift := func(worker *uuid.UUID, date time.Time, start, end planner.Clock) planner.Shift {
+		return planner.Shift{WorkerID: worker, Date: date, Start: start, End: end}
+	}
+	shifts := []planner.Shift{
+		// overnight shift of the previous day
+		shift(&w1, prevDay, planner.NewClock(22, 0), planner.NewClock(2, 0)),
+		shift(&w1, date, planner.NewClock(8, 30), planner.NewClock(12, 0)),
+		shift(&w1, date, planner.NewClock(12, 0), planner.NewClock(16, 30)),
+		shift(&w2, date, planner.NewClock(10, 0), planner.NewClock(14, 0)),
+		// ends on the next day out of range
+		shift(&w3, date, planner.NewClock(23, 0), planner.NewClock(7, 0)),
+		shift(nil, date, planner.NewClock(8, 0), planner.NewClock(16, 0)),
+	}
+	targets, err := report.ParseTargets([]string{"mon/08-18:2", "00-24:1"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		skill   string
+		skilled []planner.Worker
+		want    map[int]int
+	}{
+		{
+			name: "All workers",
+			want: map[int]int{0: 1, 1: 1, 8: 1, 9: 1, 10: 2, 11: 2, 12: 2, 13: 2, 14: 1, 15: 1, 16: 1, 23: 1},
+		},
+		{
+			name:    "Workers with skill",
+			skill:   " Forklift ",
+			skilled: []planner.Worker{{ID: w2}, {ID: w3}},
+			want:    map[int]int{10: 1, 11: 1, 12: 1, 13: 1, 23: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			if tt.skill != "" {
+				repo.EXPECT().Workers(ctx, planner.WorkersFilter{Skills: []string{"forklift"}}).Return(tt.skilled, nil)
+			}
+			repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{From: &prevDay, To: &date}).Return(shifts, nil)
+
+			slots, err := report.New(repo, report.Staffing(targets...)).
+				Coverage(ctx, report.CoverageFilter{Skill: tt.skill, From: date.Add(9 * time.Hour), To: date})
+			assert.NoError(t, err)
+			assert.Len(t, slots, 24)
+			for hour, slot := range slots {
+				target := 1
+				if hour >= 8 && hour < 18 {
+					target = 2
+				}
+				headcount := tt.want[hour]
+				assert.Equal(t, report.Slot{
+					Start: at(hour), Headcount: headcount, Target: target, Missing: max(target-headcount, 0),
+				}, slot, hour)
+			}
+		})
+	}
+}
+
+func TestCoverageFilter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	locationID := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	reporter := report.New(repo)
+
+	gomock.InOrder(
+		repo.EXPECT().Location(ctx, locationID).Return(planner.Location{ID: locationID}, nil),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{LocationID: &locationID, From: &prevDay, To: &nextDay}).Return(nil, nil),
+	)
+	slots, err := reporter.Coverage(ctx, report.CoverageFilter{LocationID: &locationID, From: date, To: nextDay})
+	assert.NoError(t, err)
+	assert.Len(t, slots, 48)
+	assert.Equal(t, report.Slot{Start: nextDay.Add(23 * time.Hour)}, slots[47])
+
+	missing := uuid.New()
+	repo.EXPECT().Location(ctx, missing).Return(planner.Location{}, planner.ErrNoRecord)
+	_, err = reporter.Coverage(ctx, report.CoverageFilter{LocationID: &missing, From: date, To: date})
+	assert.ErrorIs(t, err, planner.ErrNoRecord)
+
+	_, err = reporter.Coverage(ctx, report.CoverageFilter{From: date, To: prevDay})
+	assert.ErrorIs(t, err, planner.ErrInvalidRange)
+}
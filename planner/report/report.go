@@ -0,0 +1,149 @@
+// Package report aggregates scheduled working time of workers.
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+// Thresholds of working time after which hours are overtime,
+// zero value disables the threshold.
+type Thresholds struct {
+	// Daily applies to shifts starting on the same date.
+	Daily time.Duration
+	// Weekly applies to calendar weeks starting on Monday,
+	// hours which are already overtime by Daily don't count towards it.
+	Weekly time.Duration
+}
+
+type HoursFilter struct {
+	WorkerID *uuid.UUID `json:"worker_id"`
+	// LocationID limits hours to shifts of the location.
+	LocationID *uuid.UUID `json:"location_id"`
+	// From and To are inclusive range of shift dates.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// WorkerHours is scheduled working time of the worker in hours.
+type WorkerHours struct {
+	WorkerID uuid.UUID `json:"worker_id"`
+	Shifts   int       `json:"shifts"`
+	Total    float64   `json:"total_hours"`
+	Regular  float64   `json:"regular_hours"`
+	Overtime float64   `json:"overtime_hours"`
+}
+
+type Reporter struct {
+	repo       planner.Repository
+	thresholds Thresholds
+	targets    []Target
+}
+
+type Option func(r *Reporter)
+
+// Overtime sets thresholds of hours report, all hours are regular without them.
+func Overtime(thresholds Thresholds) Option {
+	return func(r *Reporter) {
+		r.thresholds = thresholds
+	}
+}
+
+// Staffing sets minimum headcount coverage is compared with.
+func Staffing(targets ...Target) Option {
+	return func(r *Reporter) {
+		r.targets = targets
+	}
+}
+
+func New(repo planner.Repository, opts ...Option) Reporter {
+	r := Reporter{repo: repo}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// Hours reports working time of every worker having shifts in the range.
+// Shift hours are counted to the date shift starts on.
+func (r Reporter) Hours(ctx context.Context, filter HoursFilter) ([]WorkerHours, error) {
+	from, to, err := planner.CheckRange(ctx, r.repo, filter.From, filter.To, filter.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// weekly overtime depends on hours worked since the start of the week
+	weekStart := planner.WeekStart(from)
+	days, err := r.repo.DailyHours(ctx, planner.ShiftsFilter{
+		WorkerID: filter.WorkerID, LocationID: filter.LocationID, From: &weekStart, To: &to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("daily hours: %w", err)
+	}
+
+	dailyLimit, weeklyLimit := minutes(r.thresholds.Daily), minutes(r.thresholds.Weekly)
+	var (
+		totals      []minuteTotals
+		week        time.Time
+		weekRegular int
+	)
+	for _, d := range days {
+		if len(totals) == 0 || totals[len(totals)-1].workerID != d.WorkerID {
+			totals = append(totals, minuteTotals{workerID: d.WorkerID})
+			week, weekRegular = time.Time{}, 0
+		}
+		date := planner.TruncateDate(d.Date)
+		if start := planner.WeekStart(date); !start.Equal(week) {
+			week, weekRegular = start, 0
+		}
+		regular := d.Minutes
+		if dailyLimit > 0 {
+			regular = min(regular, dailyLimit)
+		}
+		if weeklyLimit > 0 {
+			regular = min(regular, max(weeklyLimit-weekRegular, 0))
+		}
+		weekRegular += regular
+		if date.Before(from) {
+			continue
+		}
+		t := &totals[len(totals)-1]
+		t.shifts += d.Shifts
+		t.total += d.Minutes
+		t.regular += regular
+	}
+
+	report := []WorkerHours{}
+	for _, t := range totals {
+		// worker has shifts only in days of the week before the range
+		if t.shifts == 0 {
+			continue
+		}
+		report = append(report, WorkerHours{
+			WorkerID: t.workerID,
+			Shifts:   t.shifts,
+			Total:    hours(t.total),
+			Regular:  hours(t.regular),
+			Overtime: hours(t.total - t.regular),
+		})
+	}
+	return report, nil
+}
+
+type minuteTotals struct {
+	workerID       uuid.UUID
+	shifts         int
+	total, regular int
+}
+
+func minutes(d time.Duration) int {
+	return int(d / time.Minute)
+}
+
+func hours(minutes int) float64 {
+	return float64(minutes) / 60
+}
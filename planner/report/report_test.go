@@ -0,0 +1,136 @@
+package report_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/report"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestHours(t *testing.T) {
+	t.Parallel()
+	w1, w2 := uuid.New(), uuid.New()
+	// Wednesday, week starts on Monday 3rd
+	wednesday := time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC)
+	monday := wednesday.AddDate(0, 0, -2)
+	day := func(worker uuid.UUID, offset, shifts, hours int) planner.DailyHours {
+		return planner.DailyHours{WorkerID: worker, Date: wednesday.AddDate(0, 0, offset), Shifts: shifts, Minutes: hours * 60}
+	}
+
+	tests := []struct {
+		name       string
+		thresholds report.Thresholds
+		filter     report.HoursFilter
+		days       []planner.DailyHours
+		want       []report.WorkerHours
+	}{
+		{
+			name:   "No thresholds",
+			filter: report.HoursFilter{From: wednesday, To: wednesday.AddDate(0, 0, 1)},
+			days:   []planner.DailyHours{day(w1, 0, 2, 12), day(w1, 1, 1, 8), day(w2, 1, 1, 4)},
+			want: []report.WorkerHours{
+				{WorkerID: w1, Shifts: 3, Total: 20, Regular: 20},
+				{WorkerID: w2, Shifts: 1, Total: 4, Regular: 4},
+			},
+		},
+		{
+			name:       "Daily overtime",
+			thresholds: report.Thresholds{Daily: 8 * time.Hour},
+			filter:     report.HoursFilter{From: wednesday, To: wednesday.AddDate(0, 0, 1)},
+			days:       []planner.DailyHours{day(w1, 0, 2, 12), day(w1, 1, 1, 8)},
+			want:       []report.WorkerHours{{WorkerID: w1, Shifts: 3, Total: 20, Regular: 16, Overtime: 4}},
+		},
+		{
+			name:       "Weekly overtime counts days before range",
+			thresholds: report.Thresholds{Weekly: 20 * time.Hour},
+			filter:     report.HoursFilter{From: wednesday, To: wednesday.AddDate(0, 0, 1)},
+			days: []planner.DailyHours{
+				day(w1, -2, 1, 8), day(w1, -1, 1, 8), day(w1, 0, 1, 8), day(w1, 1, 1, 8),
+				// only before the range
+				day(w2, -1, 1, 8),
+			},
+			want: []report.WorkerHours{{WorkerID: w1, Shifts: 2, Total: 16, Regular: 4, Overtime: 12}},
+		},
+		{
+			name:       "Daily overtime doesn't count towards weekly",
+			thresholds: report.Thresholds{Daily: 8 * time.Hour, Weekly: 16 * time.Hour},
+			filter:     report.HoursFilter{From: monday, To: monday.AddDate(0, 0, 13)},
+			days: []planner.DailyHours{
+				day(w1, -2, 1, 10), day(w1, -1, 1, 6), day(w1, 0, 1, 6),
+				// next week
+				day(w1, 5, 1, 9),
+			},
+			want: []report.WorkerHours{{WorkerID: w1, Shifts: 4, Total: 31, Regular: 24, Overtime: 7}},
+		},
+		{
+			name:       "Minutes",
+			thresholds: report.Thresholds{Daily: 7*time.Hour + 30*time.Minute},
+			filter:     report.HoursFilter{From: wednesday, To: wednesday},
+			days:       []planner.DailyHours{{WorkerID: w1, Date: wednesday, Shifts: 1, Minutes: 495}},
+			want:       []report.WorkerHours{{WorkerID: w1, Shifts: 1, Total: 8.25, Regular: 7.5, Overtime: 0.75}},
+		},
+		{
+			name:   "No shifts",
+			filter: report.HoursFilter{From: wednesday, To: wednesday},
+			want:   []report.WorkerHours{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			from, to := monday, tt.filter.To
+			repo.EXPECT().DailyHours(ctx, planner.ShiftsFilter{From: &from, To: &to}).Return(tt.days, nil)
+
+			result, err := report.New(repo, report.Overtime(tt.thresholds)).Hours(ctx, tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestHoursFilter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	workerID, locationID := uuid.New(), uuid.New()
+	sunday := time.Date(2025, 11, 9, 15, 0, 0, 0, time.UTC)
+	monday, date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	reporter := report.New(repo)
+
+	gomock.InOrder(
+		repo.EXPECT().Location(ctx, locationID).Return(planner.Location{ID: locationID}, nil),
+		repo.EXPECT().DailyHours(ctx, planner.ShiftsFilter{
+			WorkerID: &workerID, LocationID: &locationID, From: &monday, To: &date,
+		}).Return(nil, nil),
+	)
+	result, err := reporter.Hours(ctx, report.HoursFilter{WorkerID: &workerID, LocationID: &locationID, From: sunday, To: sunday})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	missing := uuid.New()
+	repo.EXPECT().Location(ctx, missing).Return(planner.Location{}, planner.ErrNoRecord)
+	_, err = reporter.Hours(ctx, report.HoursFilter{LocationID: &missing, From: sunday, To: sunday})
+	assert.ErrorIs(t, err, planner.ErrNoRecord)
+
+	_, err = reporter.Hours(ctx, report.HoursFilter{From: sunday, To: sunday.AddDate(0, 0, -1)})
+	assert.ErrorIs(t, err, planner.ErrInvalidRange)
+	_, err = reporter.Hours(ctx, report.HoursFilter{From: sunday, To: sunday.AddDate(0, 0, planner.MaxRangeDays)})
+	assert.ErrorIs(t, err, planner.ErrInvalidRange)
+}
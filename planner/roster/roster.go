@@ -0,0 +1,263 @@
+// Package roster proposes shifts which cover staffing requirements.
+package roster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sp4rd4/wrkpln/planner"
+)
+
+// Requirement is a number of workers needed for a time slot of the day.
+type Requirement struct {
+	Date      time.Time     `json:"date" binding:"required"`
+	Start     planner.Clock `json:"start" binding:"gte=0,lte=1439"`
+	End       planner.Clock `json:"end" binding:"gte=1,lte=1440,nefield=Start"`
+	Headcount int           `json:"headcount" binding:"gte=1"`
+	// Skills are required from workers covering the requirement.
+	Skills []string `json:"skills,omitempty"`
+}
+
+type Request struct {
+	Requirements []Requirement `json:"requirements" binding:"required,min=1,dive"`
+	// WorkerIDs limits workers to choose from, all workers are used if empty.
+	WorkerIDs []uuid.UUID `json:"worker_ids"`
+	// LocationID sets location of proposed shifts, if WorkerIDs is empty
+	// only workers of the location are used.
+	LocationID *uuid.UUID `json:"location_id"`
+}
+
+// Unfilled is a requirement which can't be fully covered.
+type Unfilled struct {
+	Requirement Requirement `json:"requirement"`
+	Missing     int         `json:"missing"`
+}
+
+// Proposal is a preview of generated shifts, it's not stored.
+type Proposal struct {
+	Shifts   []planner.Shift `json:"shifts"`
+	Unfilled []Unfilled      `json:"unfilled"`
+}
+
+// Eligibility reports whether worker of the shift can take it,
+// regardless of worker's other shifts.
+type Eligibility func(planner.Shift) bool
+
+type Generator struct {
+	repo   planner.Repository
+	policy planner.ConflictPolicy
+	rules  []planner.ComplianceRule
+}
+
+// New returns generator checking proposed shifts against the conflict policy
+// and compliance rules, shifts are checked when they are committed the same way.
+func New(repo planner.Repository, policy planner.ConflictPolicy, rules ...planner.ComplianceRule) Generator {
+	return Generator{repo: repo, policy: policy, rules: rules}
+}
+
+// Generate proposes shifts for requirements taking into account
+// shifts already booked for the workers.
+func (g Generator) Generate(ctx context.Context, req Request) (Proposal, error) {
+	total := 0
+	for i, r := range req.Requirements {
+		req.Requirements[i].Date = planner.TruncateDate(r.Date)
+		req.Requirements[i].Skills = planner.NormalizeSkills(r.Skills)
+		total += r.Headcount
+	}
+	// proposal is committed as a batch, so it has the same size limit
+	if total > planner.MaxBatchSize {
+		return Proposal{}, planner.ErrInvalidBatch
+	}
+
+	if req.LocationID != nil {
+		if _, err := g.repo.Location(ctx, *req.LocationID); err != nil {
+			return Proposal{}, fmt.Errorf("get location: %w", err)
+		}
+	}
+	workers, err := g.workers(ctx, req.WorkerIDs, req.LocationID)
+	if err != nil {
+		return Proposal{}, err
+	}
+
+	first, last := span(req.Requirements)
+	days := max(planner.ConflictDays(g.policy), planner.ComplianceDays(g.rules))
+	from, to := first.AddDate(0, 0, -days), last.AddDate(0, 0, days)
+	// shifts of all locations are checked, so worker isn't booked at two sites at once
+	existing, err := g.repo.Shifts(ctx, planner.ShiftsFilter{From: &from, To: &to})
+	if err != nil {
+		return Proposal{}, fmt.Errorf("list shifts: %w", err)
+	}
+
+	available, err := g.availability(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return Proposal{}, err
+	}
+	qualified := make(map[uuid.UUID]planner.Worker, len(workers))
+	ids := make([]uuid.UUID, 0, len(workers))
+	for _, w := range workers {
+		qualified[w.ID] = w
+		ids = append(ids, w.ID)
+	}
+	eligible := func(shift planner.Shift) bool {
+		return len(qualified[*shift.WorkerID].MissingSkills(shift)) == 0 && available(shift)
+	}
+
+	proposal := Propose(req.Requirements, ids, existing, g.policy, g.rules, eligible)
+	for i := range proposal.Shifts {
+		proposal.Shifts[i].LocationID = req.LocationID
+	}
+	return proposal, nil
+}
+
+// availability loads availability of all workers for the range.
+func (g Generator) availability(ctx context.Context, from, to time.Time) (Eligibility, error) {
+	windows, err := g.repo.Availabilities(ctx, planner.AvailabilityFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list availabilities: %w", err)
+	}
+	absences, err := g.repo.Unavailabilities(ctx, planner.UnavailabilityFilter{From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("list unavailabilities: %w", err)
+	}
+	approved := planner.LeaveApproved
+	leaves, err := g.repo.Leaves(ctx, planner.LeavesFilter{Status: &approved, From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("list leaves: %w", err)
+	}
+	for _, l := range leaves {
+		absences = append(absences, l.Unavailability())
+	}
+	workerWindows := make(map[uuid.UUID][]planner.Availability)
+	for _, w := range windows {
+		workerWindows[w.WorkerID] = append(workerWindows[w.WorkerID], w)
+	}
+	workerAbsences := make(map[uuid.UUID][]planner.Unavailability)
+	for _, a := range absences {
+		workerAbsences[a.WorkerID] = append(workerAbsences[a.WorkerID], a)
+	}
+	return func(shift planner.Shift) bool {
+		return planner.CheckAvailability(shift, workerWindows[*shift.WorkerID], workerAbsences[*shift.WorkerID]) == nil
+	}, nil
+}
+
+func (g Generator) workers(ctx context.Context, ids []uuid.UUID, locationID *uuid.UUID) ([]planner.Worker, error) {
+	if len(ids) == 0 {
+		workers, err := g.repo.Workers(ctx, planner.WorkersFilter{
+			LocationID: locationID, Sort: planner.Sort{Field: planner.SortByName},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list workers: %w", err)
+		}
+		return workers, nil
+	}
+	workers := make([]planner.Worker, 0, len(ids))
+	for _, id := range ids {
+		worker, err := g.repo.Worker(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get worker %s: %w", id, err)
+		}
+		workers = append(workers, worker)
+	}
+	return workers, nil
+}
+
+// Propose greedily assigns every requirement slot to the worker
+// with the least scheduled time whose shift doesn't conflict
+// with other shifts according to policy and doesn't break blocking compliance rules.
+// Workers not eligible for the shift are skipped,
+// nil eligible means all workers are. Ties are resolved by workers order.
+// Time of existing shifts counts to workers load only for requirements dates range.
+func Propose(
+	reqs []Requirement, workers []uuid.UUID, existing []planner.Shift,
+	policy planner.ConflictPolicy, rules []planner.ComplianceRule, eligible Eligibility,
+) Proposal {
+	first, last := span(reqs)
+	reqs = append([]Requirement(nil), reqs...)
+	sort.SliceStable(reqs, func(i, j int) bool {
+		if !reqs[i].Date.Equal(reqs[j].Date) {
+			return reqs[i].Date.Before(reqs[j].Date)
+		}
+		return reqs[i].Start < reqs[j].Start
+	})
+
+	booked := make(map[uuid.UUID][]planner.Shift, len(workers))
+	load := make(map[uuid.UUID]time.Duration, len(workers))
+	for _, s := range existing {
+		if s.Open() {
+			continue
+		}
+		booked[*s.WorkerID] = append(booked[*s.WorkerID], s)
+		if !s.Date.Before(first) && !s.Date.After(last) {
+			load[*s.WorkerID] += s.Duration()
+		}
+	}
+
+	proposal := Proposal{Shifts: []planner.Shift{}, Unfilled: []Unfilled{}}
+	for _, r := range reqs {
+		missing := r.Headcount
+		for ; missing > 0; missing-- {
+			best := -1
+			for i, w := range workers {
+				shift := planner.Shift{WorkerID: &w, Date: r.Date, Start: r.Start, End: r.End, Skills: r.Skills}
+				if eligible != nil && !eligible(shift) || conflicts(policy, shift, booked[w]) || violates(rules, shift, booked[w]) {
+					continue
+				}
+				if best == -1 || load[w] < load[workers[best]] {
+					best = i
+				}
+			}
+			if best == -1 {
+				break
+			}
+			w := workers[best]
+			shift := planner.Shift{WorkerID: &w, Date: r.Date, Start: r.Start, End: r.End, Skills: r.Skills}
+			booked[w] = append(booked[w], shift)
+			load[w] += shift.Duration()
+			proposal.Shifts = append(proposal.Shifts, shift)
+		}
+		if missing > 0 {
+			proposal.Unfilled = append(proposal.Unfilled, Unfilled{Requirement: r, Missing: missing})
+		}
+	}
+	return proposal
+}
+
+func span(reqs []Requirement) (first, last time.Time) {
+	if len(reqs) == 0 {
+		return first, last
+	}
+	first, last = reqs[0].Date, reqs[0].Date
+	for _, r := range reqs {
+		if r.Date.Before(first) {
+			first = r.Date
+		}
+		if r.Date.After(last) {
+			last = r.Date
+		}
+	}
+	return first, last
+}
+
+// violates reports whether shift breaks blocking compliance rules
+// in schedule of worker with other shifts.
+func violates(rules []planner.ComplianceRule, shift planner.Shift, others []planner.Shift) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	// proposed shifts have no IDs yet, temporary one tells violations of the shift apart
+	shift.ID = uuid.New()
+	_, err := planner.CheckShiftCompliance(rules, others, shift)
+	return err != nil
+}
+
+func conflicts(policy planner.ConflictPolicy, shift planner.Shift, others []planner.Shift) bool {
+	for _, other := range others {
+		if policy.Conflict(shift, other) != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package roster_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/roster"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestPropose(t *testing.T) {
+	t.Parallel()
+	w1, w2, w3 := uuid.New(), uuid.New(), uuid.New()
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	req := func(date time.Time, start, end, headcount int) roster.Requirement {
+		return roster.Requirement{Date: date, Start: planner.NewClock(start, 0), End: planner.NewClock(end, 0), Headcount: headcount}
+	}
+	shift := func(worker uuid.UUID, date time.Time, start, end int) planner.Shift {
+		return planner.Shift{WorkerID: &worker, Date: date, Start: planner.NewClock(start, 0), End: planner.NewClock(end, 0)}
+	}
+
+	tests := []struct {
+		name     string
+		reqs     []roster.Requirement
+		workers  []uuid.UUID
+		existing []planner.Shift
+		policy   planner.ConflictPolicy
+		rules    []planner.ComplianceRule
+		eligible roster.Eligibility
+		want     roster.Proposal
+	}{
+		{
+			name:    "Hours spread across workers",
+			reqs:    []roster.Requirement{req(nextDay, 8, 16, 1), req(day, 8, 16, 1), req(day, 16, 24, 1)},
+			workers: []uuid.UUID{w1, w2},
+			policy:  planner.NoOverlap{},
+			want: roster.Proposal{
+				Shifts: []planner.Shift{
+					shift(w1, day, 8, 16),
+					shift(w2, day, 16, 24),
+					shift(w1, nextDay, 8, 16),
+				},
+				Unfilled: []roster.Unfilled{},
+			},
+		},
+		{
+			name:     "Existing shifts count to load",
+			reqs:     []roster.Requirement{req(nextDay, 8, 16, 1)},
+			workers:  []uuid.UUID{w1, w2},
+			existing: []planner.Shift{shift(w1, day, 8, 16), shift(w1, nextDay, 0, 6)},
+			policy:   planner.NoOverlap{},
+			want: roster.Proposal{
+				Shifts:   []planner.Shift{shift(w2, nextDay, 8, 16)},
+				Unfilled: []roster.Unfilled{},
+			},
+		},
+		{
+			name:     "Conflicting workers skipped and unfilled reported",
+			reqs:     []roster.Requirement{req(nextDay, 6, 14, 3)},
+			workers:  []uuid.UUID{w1, w2, w3},
+			existing: []planner.Shift{shift(w2, day, 22, 7)},
+			policy:   planner.MinRest{Rest: 11 * time.Hour},
+			want: roster.Proposal{
+				Shifts:   []planner.Shift{shift(w1, nextDay, 6, 14), shift(w3, nextDay, 6, 14)},
+				Unfilled: []roster.Unfilled{{Requirement: req(nextDay, 6, 14, 3), Missing: 1}},
+			},
+		},
+		{
+			name:     "Not eligible workers skipped",
+			reqs:     []roster.Requirement{req(day, 8, 16, 1), req(day, 16, 24, 1)},
+			workers:  []uuid.UUID{w1, w2},
+			policy:   planner.NoOverlap{},
+			eligible: func(s planner.Shift) bool { return !s.AssignedTo(w1) || s.Start < planner.NewClock(12, 0) },
+			want: roster.Proposal{
+				Shifts:   []planner.Shift{shift(w1, day, 8, 16), shift(w2, day, 16, 24)},
+				Unfilled: []roster.Unfilled{},
+			},
+		},
+		{
+			name:    "Workers breaking blocking rules skipped",
+			reqs:    []roster.Requirement{req(nextDay, 8, 16, 2)},
+			workers: []uuid.UUID{w1, w2},
+			// shift of the previous day doesn't count to load, but counts to weekly hours
+			existing: []planner.Shift{shift(w1, day, 8, 16)},
+			policy:   planner.OnePerDay{},
+			rules:    []planner.ComplianceRule{{Rule: planner.MaxWeeklyHours{Max: 12 * time.Hour}}},
+			want: roster.Proposal{
+				Shifts:   []planner.Shift{shift(w2, nextDay, 8, 16)},
+				Unfilled: []roster.Unfilled{{Requirement: req(nextDay, 8, 16, 2), Missing: 1}},
+			},
+		},
+		{
+			name:     "Warning rules don't skip workers",
+			reqs:     []roster.Requirement{req(nextDay, 8, 16, 2)},
+			workers:  []uuid.UUID{w1, w2},
+			existing: []planner.Shift{shift(w1, day, 8, 16)},
+			policy:   planner.OnePerDay{},
+			rules:    []planner.ComplianceRule{{Rule: planner.MaxWeeklyHours{Max: 12 * time.Hour}, Warn: true}},
+			want: roster.Proposal{
+				Shifts:   []planner.Shift{shift(w1, nextDay, 8, 16), shift(w2, nextDay, 8, 16)},
+				Unfilled: []roster.Unfilled{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := roster.Propose(tt.reqs, tt.workers, tt.existing, tt.policy, tt.rules, tt.eligible)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	w1, w2, w3 := uuid.New(), uuid.New(), uuid.New()
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	from, to := day.AddDate(0, 0, -1), day.AddDate(0, 0, 1)
+	end := to.AddDate(0, 0, 1)
+	leave := planner.LeaveRequest{WorkerID: w2, From: day, To: day.AddDate(0, 0, 7), Status: planner.LeaveApproved}
+	approved := planner.LeaveApproved
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	gen := roster.New(repo, planner.OnePerDay{})
+	repo.EXPECT().Workers(ctx, planner.WorkersFilter{Sort: planner.Sort{Field: planner.SortByName}}).Return([]planner.Worker{
+		{ID: w1, Skills: []string{"forklift"}}, {ID: w2, Skills: []string{"forklift"}}, {ID: w3},
+	}, nil)
+	repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{From: &from, To: &to}).Return(nil, nil)
+	repo.EXPECT().Availabilities(ctx, planner.AvailabilityFilter{}).Return(nil, nil)
+	repo.EXPECT().Unavailabilities(ctx, planner.UnavailabilityFilter{From: &from, To: &end}).Return(nil, nil)
+	repo.EXPECT().Leaves(ctx, planner.LeavesFilter{Status: &approved, From: &from, To: &end}).Return([]planner.LeaveRequest{leave}, nil)
+
+	result, err := gen.Generate(ctx, roster.Request{Requirements: []roster.Requirement{
+		{Date: day.Add(15 * time.Hour), Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0), Headcount: 2, Skills: []string{"Forklift"}},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, result.Shifts, 1)
+	assert.Equal(t, day, result.Shifts[0].Date)
+	assert.Equal(t, &w1, result.Shifts[0].WorkerID)
+	assert.Equal(t, []string{"forklift"}, result.Shifts[0].Skills)
+	assert.Equal(t, 1, result.Unfilled[0].Missing)
+
+	_, err = gen.Generate(ctx, roster.Request{Requirements: []roster.Requirement{
+		{Date: day, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0), Headcount: planner.MaxBatchSize + 1},
+	}})
+	assert.ErrorIs(t, err, planner.ErrInvalidBatch)
+}
+
+func TestGenerateLocation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	w1, w2 := uuid.New(), uuid.New()
+	site, other := uuid.New(), uuid.New()
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	from, to := day.AddDate(0, 0, -1), day.AddDate(0, 0, 1)
+	// worker of the site is already booked at the other one
+	booked := planner.Shift{ID: uuid.New(), WorkerID: &w1, LocationID: &other, Date: day, Start: planner.NewClock(6, 0), End: planner.NewClock(10, 0)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+
+	gen := roster.New(repo, planner.OnePerDay{})
+	repo.EXPECT().Location(ctx, site).Return(planner.Location{ID: site}, nil)
+	repo.EXPECT().Workers(ctx, planner.WorkersFilter{LocationID: &site, Sort: planner.Sort{Field: planner.SortByName}}).Return([]planner.Worker{
+		{ID: w1, LocationID: &site}, {ID: w2, LocationID: &site},
+	}, nil)
+	repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{From: &from, To: &to}).Return([]planner.Shift{booked}, nil)
+	repo.EXPECT().Availabilities(ctx, gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Unavailabilities(ctx, gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Leaves(ctx, gomock.Any()).Return(nil, nil)
+
+	result, err := gen.Generate(ctx, roster.Request{LocationID: &site, Requirements: []roster.Requirement{
+		{Date: day, Start: planner.NewClock(12, 0), End: planner.NewClock(20, 0), Headcount: 2},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, result.Shifts, 1)
+	assert.Equal(t, &w2, result.Shifts[0].WorkerID)
+	assert.Equal(t, &site, result.Shifts[0].LocationID)
+	assert.Equal(t, 1, result.Unfilled[0].Missing)
+
+	repo.EXPECT().Location(ctx, other).Return(planner.Location{}, planner.ErrNoRecord)
+	_, err = gen.Generate(ctx, roster.Request{LocationID: &other, Requirements: []roster.Requirement{
+		{Date: day, Start: planner.NewClock(12, 0), End: planner.NewClock(20, 0), Headcount: 1},
+	}})
+	assert.ErrorIs(t, err, planner.ErrNoRecord)
+}
@@ -0,0 +1,31 @@
+package planner
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeSkills lowercases and sorts skills dropping empty and duplicate ones,
+// nil is returned if there are no skills.
+func NormalizeSkills(skills []string) []string {
+	var normalized []string
+	for _, s := range skills {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s != "" {
+			normalized = append(normalized, s)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// MissingSkills returns skills required by shift that worker doesn't have.
+func (w Worker) MissingSkills(shift Shift) []string {
+	var missing []string
+	for _, s := range shift.Skills {
+		if !slices.Contains(w.Skills, s) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,161 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestNormalizeSkills(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{name: "Empty", input: nil, want: nil},
+		{name: "Blank only", input: []string{" ", ""}, want: nil},
+		{name: "Case and spaces", input: []string{" Forklift", "first aid "}, want: []string{"first aid", "forklift"}},
+		{name: "Duplicates", input: []string{"forklift", "FORKLIFT", "cashier"}, want: []string{"cashier", "forklift"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, planner.NormalizeSkills(tt.input))
+		})
+	}
+}
+
+func TestCreateShiftUnqualified(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1 := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := planner.Shift{
+		WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0),
+		Skills: []string{"Forklift", "supervisor"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1, Skills: []string{"forklift"}}, nil),
+	)
+
+	plan := planner.New(repo, planner.UUIDGenerator(genID))
+	_, err := plan.CreateShift(ctx, shift)
+	assert.ErrorIs(t, err, planner.ErrUnqualified)
+	assert.ErrorContains(t, err, "missing supervisor")
+}
+
+func TestChangeWorkerSkills(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.New()
+	now := time.Date(2025, 11, 3, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	locationID := uuid.New()
+	stored := planner.Worker{ID: id1, Name: "Ann", Skills: []string{"cashier", "forklift"}}
+	forklift := planner.Shift{
+		ID: uuid.New(), WorkerID: &id1, Date: today.AddDate(0, 0, 1),
+		Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0), Skills: []string{"forklift"},
+	}
+	booked := []planner.Shift{{ID: uuid.New(), WorkerID: &id1, Date: today}, forklift}
+
+	tests := []struct {
+		name       string
+		patch      planner.WorkerPatch
+		listShifts bool
+		expErr     error
+	}{
+		{name: "Name only", patch: planner.WorkerPatch{Name: ptr("Anne")}},
+		{name: "Location only", patch: planner.WorkerPatch{LocationID: &locationID}},
+		{name: "Same skills", patch: planner.WorkerPatch{Skills: &[]string{"Forklift", "cashier"}}},
+		{name: "Unused skill removed", patch: planner.WorkerPatch{Skills: &[]string{"Forklift"}}, listShifts: true},
+		{
+			name: "Required skill removed", patch: planner.WorkerPatch{Skills: &[]string{"cashier"}},
+			listShifts: true, expErr: planner.ErrUnqualified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Worker(ctx, id1).Return(stored, nil),
+			}
+			if tt.patch.LocationID != nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().Location(ctx, locationID).Return(planner.Location{ID: locationID}, nil),
+				)
+			}
+			if tt.listShifts {
+				expectations = append(
+					expectations,
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &today}).Return(booked, nil),
+				)
+			}
+			if tt.expErr == nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().UpdateWorker(ctx, gomock.Any()).Return(nil),
+					expectAudit(ctx, repo, "worker", "update", id1),
+				)
+			}
+			gomock.InOrder(expectations...)
+
+			plan := planner.New(repo, planner.TimeSource(func() time.Time { return now }))
+			_, err := plan.PatchWorker(ctx, id1, tt.patch)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				assert.ErrorContains(t, err, "missing forklift")
+				var conflictErr planner.ConflictError
+				assert.ErrorAs(t, err, &conflictErr)
+				assert.Equal(t, forklift, conflictErr.Shift)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpdateWorkerUnqualified(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1 := uuid.New()
+	now := time.Date(2025, 11, 3, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := planner.Shift{
+		ID: uuid.New(), WorkerID: &id1, Date: today,
+		Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0), Skills: []string{"forklift"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1, Name: "Ann", Skills: []string{"forklift"}}, nil),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &today}).Return([]planner.Shift{shift}, nil),
+	)
+
+	plan := planner.New(repo, planner.TimeSource(func() time.Time { return now }))
+	_, err := plan.UpdateWorker(ctx, planner.Worker{ID: id1, Name: "Ann"})
+	assert.ErrorIs(t, err, planner.ErrUnqualified)
+}
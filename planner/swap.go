@@ -0,0 +1,297 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type SwapStatus string
+
+const (
+	SwapPending   SwapStatus = "pending"
+	SwapAccepted  SwapStatus = "accepted"
+	SwapDeclined  SwapStatus = "declined"
+	SwapCancelled SwapStatus = "cancelled"
+)
+
+// swapActions names status changes in audit log.
+var swapActions = map[SwapStatus]string{
+	SwapAccepted:  "accept",
+	SwapDeclined:  "decline",
+	SwapCancelled: "cancel",
+}
+
+// swapTransitions lists statuses swap request can move to from the status.
+var swapTransitions = map[SwapStatus][]SwapStatus{
+	SwapPending: {SwapAccepted, SwapDeclined, SwapCancelled},
+}
+
+func (s SwapStatus) Valid() bool {
+	switch s {
+	case SwapPending, SwapAccepted, SwapDeclined, SwapCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanBecome reports whether swap request in status s can be moved to next status.
+func (s SwapStatus) CanBecome(next SwapStatus) bool {
+	for _, status := range swapTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// SwapRequest is a proposal to hand shift over to another worker
+// or to exchange it with the shift of another worker.
+// It takes effect once accepted by the receiving worker.
+type SwapRequest struct {
+	ID      uuid.UUID `json:"id"`
+	ShiftID uuid.UUID `json:"shift_id" binding:"required"`
+	// OtherShiftID is set for exchange of shifts.
+	OtherShiftID *uuid.UUID `json:"other_shift_id"`
+	FromWorkerID uuid.UUID  `json:"from_worker_id"`
+	// ToWorkerID is the receiving worker,
+	// for exchange it's the worker of the other shift.
+	ToWorkerID uuid.UUID  `json:"to_worker_id" binding:"required_without=OtherShiftID"`
+	Status     SwapStatus `json:"status"`
+	TenantID   string     `json:"-"`
+}
+
+type SwapsFilter struct {
+	// WorkerID selects requests where worker is either side.
+	WorkerID *uuid.UUID  `json:"worker_id"`
+	Status   *SwapStatus `json:"status"`
+}
+
+// TransferShift reassigns shift to another worker.
+func (w Work) TransferShift(ctx context.Context, id, workerID uuid.UUID) (Shift, error) {
+	var shift Shift
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		var err error
+		shift, err = repo.Shift(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		shift, err = w.transfer(ctx, repo, shift, workerID)
+		return err
+	})
+	if err != nil {
+		return Shift{}, fmt.Errorf("transfer shift transaction: %w", err)
+	}
+	return shift, nil
+}
+
+// SwapShifts exchanges workers of two shifts.
+func (w Work) SwapShifts(ctx context.Context, id, otherID uuid.UUID) ([]Shift, error) {
+	var shifts []Shift
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		shift, err := repo.Shift(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		other, err := repo.Shift(ctx, otherID)
+		if err != nil {
+			return fmt.Errorf("get other shift: %w", err)
+		}
+		shifts, err = w.exchange(ctx, repo, shift, other)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("swap shifts transaction: %w", err)
+	}
+	return shifts, nil
+}
+
+func (w Work) transfer(ctx context.Context, repo Repository, shift Shift, workerID uuid.UUID) (Shift, error) {
+	// open shifts are claimed instead
+	if shift.Open() {
+		return Shift{}, fmt.Errorf("open shift: %w", ErrInvalidSwap)
+	}
+	if shift.AssignedTo(workerID) {
+		return Shift{}, fmt.Errorf("same worker: %w", ErrInvalidSwap)
+	}
+	before := shift
+	shift.WorkerID = &workerID
+	if err := w.checkShift(ctx, repo, &shift); err != nil {
+		return Shift{}, err
+	}
+	if err := repo.UpdateShift(ctx, shift); err != nil {
+		return Shift{}, fmt.Errorf("updating shift: %w", err)
+	}
+	if err := w.audit(ctx, repo, "shift", "transfer", shift.ID, before, shift); err != nil {
+		return Shift{}, err
+	}
+	return shift, nil
+}
+
+func (w Work) exchange(ctx context.Context, repo Repository, shift, other Shift) ([]Shift, error) {
+	if shift.Open() || other.Open() {
+		return nil, fmt.Errorf("open shift: %w", ErrInvalidSwap)
+	}
+	if shift.AssignedTo(*other.WorkerID) {
+		return nil, fmt.Errorf("same worker: %w", ErrInvalidSwap)
+	}
+	before := []Shift{shift, other}
+	shift.WorkerID, other.WorkerID = other.WorkerID, shift.WorkerID
+	// both shifts are moved before the check, so they don't conflict
+	// with their previous assignments, transaction is rolled back on conflict
+	for _, s := range []Shift{shift, other} {
+		if err := repo.UpdateShift(ctx, s); err != nil {
+			return nil, fmt.Errorf("updating shift: %w", err)
+		}
+	}
+	for _, s := range []*Shift{&shift, &other} {
+		if err := w.checkShift(ctx, repo, s); err != nil {
+			return nil, err
+		}
+	}
+	for i, s := range []Shift{shift, other} {
+		if err := w.audit(ctx, repo, "shift", "swap", s.ID, before[i], s); err != nil {
+			return nil, err
+		}
+	}
+	return []Shift{shift, other}, nil
+}
+
+func (w Work) RequestSwap(ctx context.Context, swap SwapRequest) (SwapRequest, error) {
+	swap.ID = w.uuid()
+	swap.Status = SwapPending
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		shift, err := repo.Shift(ctx, swap.ShiftID)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		if shift.Open() {
+			return fmt.Errorf("open shift: %w", ErrInvalidSwap)
+		}
+		swap.FromWorkerID = *shift.WorkerID
+		if swap.OtherShiftID != nil {
+			other, err := repo.Shift(ctx, *swap.OtherShiftID)
+			if err != nil {
+				return fmt.Errorf("get other shift: %w", err)
+			}
+			if other.Open() {
+				return fmt.Errorf("open other shift: %w", ErrInvalidSwap)
+			}
+			swap.ToWorkerID = *other.WorkerID
+		} else if _, err := repo.Worker(ctx, swap.ToWorkerID); err != nil {
+			return fmt.Errorf("get worker: %w", err)
+		}
+		if swap.FromWorkerID == swap.ToWorkerID {
+			return fmt.Errorf("same worker: %w", ErrInvalidSwap)
+		}
+		if err := repo.CreateSwap(ctx, swap); err != nil {
+			return fmt.Errorf("creating swap: %w", err)
+		}
+		return w.audit(ctx, repo, "swap", "create", swap.ID, nil, swap)
+	})
+	if err != nil {
+		return SwapRequest{}, fmt.Errorf("request swap transaction: %w", err)
+	}
+	return swap, nil
+}
+
+func (w Work) Swap(ctx context.Context, id uuid.UUID) (SwapRequest, error) {
+	swap, err := w.repo.Swap(ctx, id)
+	if err != nil {
+		return SwapRequest{}, fmt.Errorf("get swap: %w", err)
+	}
+	return swap, nil
+}
+
+func (w Work) Swaps(ctx context.Context, filter SwapsFilter) ([]SwapRequest, error) {
+	if filter.Status != nil && !filter.Status.Valid() {
+		return nil, fmt.Errorf("status %q: %w", *filter.Status, ErrInvalidFilter)
+	}
+	swaps, err := w.repo.Swaps(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list swaps: %w", err)
+	}
+	return swaps, nil
+}
+
+// AcceptSwap accepts pending swap request and performs it,
+// shifts must still belong to the same workers as when request was made.
+func (w Work) AcceptSwap(ctx context.Context, id uuid.UUID) (SwapRequest, []Shift, error) {
+	var swap SwapRequest
+	var shifts []Shift
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		var err error
+		swap, err = w.moveSwap(ctx, repo, id, SwapAccepted)
+		if err != nil {
+			return err
+		}
+		shift, err := repo.Shift(ctx, swap.ShiftID)
+		if err != nil {
+			return fmt.Errorf("get shift: %w", err)
+		}
+		if !shift.AssignedTo(swap.FromWorkerID) {
+			return fmt.Errorf("shift reassigned: %w", ErrInvalidSwap)
+		}
+		if swap.OtherShiftID == nil {
+			shift, err = w.transfer(ctx, repo, shift, swap.ToWorkerID)
+			shifts = []Shift{shift}
+			return err
+		}
+		other, err := repo.Shift(ctx, *swap.OtherShiftID)
+		if err != nil {
+			return fmt.Errorf("get other shift: %w", err)
+		}
+		if !other.AssignedTo(swap.ToWorkerID) {
+			return fmt.Errorf("other shift reassigned: %w", ErrInvalidSwap)
+		}
+		shifts, err = w.exchange(ctx, repo, shift, other)
+		return err
+	})
+	if err != nil {
+		return SwapRequest{}, nil, fmt.Errorf("accept swap transaction: %w", err)
+	}
+	return swap, shifts, nil
+}
+
+func (w Work) DeclineSwap(ctx context.Context, id uuid.UUID) (SwapRequest, error) {
+	return w.setSwapStatus(ctx, id, SwapDeclined)
+}
+
+func (w Work) CancelSwap(ctx context.Context, id uuid.UUID) (SwapRequest, error) {
+	return w.setSwapStatus(ctx, id, SwapCancelled)
+}
+
+func (w Work) setSwapStatus(ctx context.Context, id uuid.UUID, status SwapStatus) (SwapRequest, error) {
+	var swap SwapRequest
+	err := w.repo.Transaction(ctx, func(repo Repository) error {
+		var err error
+		swap, err = w.moveSwap(ctx, repo, id, status)
+		return err
+	})
+	if err != nil {
+		return SwapRequest{}, fmt.Errorf("%s swap transaction: %w", status, err)
+	}
+	return swap, nil
+}
+
+// moveSwap moves swap request to the next status if transition is allowed.
+func (w Work) moveSwap(ctx context.Context, repo Repository, id uuid.UUID, next SwapStatus) (SwapRequest, error) {
+	swap, err := repo.Swap(ctx, id)
+	if err != nil {
+		return SwapRequest{}, fmt.Errorf("get swap: %w", err)
+	}
+	if !swap.Status.CanBecome(next) {
+		return SwapRequest{}, fmt.Errorf("%s to %s: %w", swap.Status, next, ErrInvalidTransition)
+	}
+	if err := repo.UpdateSwapStatus(ctx, id, swap.Status, next); err != nil {
+		return SwapRequest{}, fmt.Errorf("updating swap status: %w", err)
+	}
+	before := swap
+	swap.Status = next
+	if err := w.audit(ctx, repo, "swap", swapActions[next], id, before, swap); err != nil {
+		return SwapRequest{}, err
+	}
+	return swap, nil
+}
@@ -0,0 +1,171 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	repomock "github.com/sp4rd4/wrkpln/repository/mock"
+)
+
+func TestTransferShift(t *testing.T) {
+	t.Parallel()
+	id1, id2 := uuid.New(), uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+	shift := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	moved := shift
+	moved.WorkerID = &id2
+
+	tests := []struct {
+		name         string
+		workerID     uuid.UUID
+		workerShifts []planner.Shift
+		expErr       error
+	}{
+		{name: "Success", workerID: id2},
+		{name: "Same worker", workerID: id1, expErr: planner.ErrInvalidSwap},
+		{
+			name:         "Receiving worker booked",
+			workerID:     id2,
+			workerShifts: []planner.Shift{{ID: uuid.New(), WorkerID: &id2, Date: date, Start: planner.NewClock(18, 0), End: planner.NewClock(22, 0)}},
+			expErr:       planner.ErrDayAlreadyBooked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Shift(ctx, shift.ID).Return(shift, nil),
+			}
+			if tt.workerID != id1 {
+				expectations = append(
+					expectations,
+					repo.EXPECT().Worker(ctx, id2).Return(planner.Worker{ID: id2}, nil),
+					expectAvailable(ctx, repo, id2),
+					repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id2, From: &prevDay, To: &nextDay}).Return(tt.workerShifts, nil),
+				)
+			}
+			if tt.expErr == nil {
+				expectations = append(
+					expectations,
+					repo.EXPECT().UpdateShift(ctx, moved).Return(nil),
+					expectAudit(ctx, repo, "shift", "transfer", shift.ID),
+				)
+			}
+			gomock.InOrder(expectations...)
+
+			plan := planner.New(repo)
+			result, err := plan.TransferShift(ctx, shift.ID, tt.workerID)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, moved, result)
+		})
+	}
+}
+
+func TestSwapShifts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	id1, id2 := uuid.New(), uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	prevDay, nextDay := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+	nextDayBefore, nextDayAfter := date, date.AddDate(0, 0, 2)
+	shift := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	other := planner.Shift{ID: uuid.New(), WorkerID: &id2, Date: nextDay, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	swapped := []planner.Shift{shift, other}
+	swapped[0].WorkerID, swapped[1].WorkerID = &id2, &id1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+		repo.EXPECT().Shift(ctx, shift.ID).Return(shift, nil),
+		repo.EXPECT().Shift(ctx, other.ID).Return(other, nil),
+		repo.EXPECT().UpdateShift(ctx, swapped[0]).Return(nil),
+		repo.EXPECT().UpdateShift(ctx, swapped[1]).Return(nil),
+		// shifts are checked with both of them already moved
+		repo.EXPECT().Worker(ctx, id2).Return(planner.Worker{ID: id2}, nil),
+		expectAvailable(ctx, repo, id2),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id2, From: &prevDay, To: &nextDay}).Return(swapped[:1], nil),
+		repo.EXPECT().Worker(ctx, id1).Return(planner.Worker{ID: id1}, nil),
+		expectAvailable(ctx, repo, id1),
+		repo.EXPECT().Shifts(ctx, planner.ShiftsFilter{WorkerID: &id1, From: &nextDayBefore, To: &nextDayAfter}).Return(swapped[1:], nil),
+		expectAudit(ctx, repo, "shift", "swap", shift.ID),
+		expectAudit(ctx, repo, "shift", "swap", other.ID),
+	)
+
+	plan := planner.New(repo)
+	result, err := plan.SwapShifts(ctx, shift.ID, other.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, swapped, result)
+}
+
+func TestAcceptSwap(t *testing.T) {
+	t.Parallel()
+	id1, id2, id3 := uuid.New(), uuid.New(), uuid.New()
+	swapID := uuid.New()
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	shift := planner.Shift{ID: uuid.New(), WorkerID: &id1, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	swap := planner.SwapRequest{ID: swapID, ShiftID: shift.ID, FromWorkerID: id1, ToWorkerID: id2}
+
+	tests := []struct {
+		name      string
+		status    planner.SwapStatus
+		shiftWith uuid.UUID
+		expErr    error
+	}{
+		{name: "Already declined", status: planner.SwapDeclined, expErr: planner.ErrInvalidTransition},
+		{name: "Shift reassigned", status: planner.SwapPending, shiftWith: id3, expErr: planner.ErrInvalidSwap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomock.NewMockRepository(ctrl)
+
+			stored := swap
+			stored.Status = tt.status
+			current := shift
+			current.WorkerID = &tt.shiftWith
+			expectations := []any{
+				repo.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, f transaction) error { return f(repo) }),
+				repo.EXPECT().Swap(ctx, swapID).Return(stored, nil),
+			}
+			if tt.status == planner.SwapPending {
+				expectations = append(
+					expectations,
+					repo.EXPECT().UpdateSwapStatus(ctx, swapID, planner.SwapPending, planner.SwapAccepted).Return(nil),
+					expectAudit(ctx, repo, "swap", "accept", swapID),
+					repo.EXPECT().Shift(ctx, shift.ID).Return(current, nil),
+				)
+			}
+			gomock.InOrder(expectations...)
+
+			plan := planner.New(repo)
+			_, _, err := plan.AcceptSwap(ctx, swapID)
+			assert.ErrorIs(t, err, tt.expErr)
+		})
+	}
+}
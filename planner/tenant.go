@@ -0,0 +1,27 @@
+package planner
+
+import (
+	"context"
+	"regexp"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns context of the tenant organisation, repository
+// keeps records of every tenant apart and sets their TenantID.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns tenant of the context, false is returned if it isn't set.
+func TenantFrom(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidTenant reports whether tenant is 1 to 64 letters, digits, underscores or dashes.
+func ValidTenant(tenant string) bool {
+	return tenantPattern.MatchString(tenant)
+}
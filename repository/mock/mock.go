@@ -18,6 +18,44 @@ import (
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockLocationGetter is a mock of LocationGetter interface.
+type MockLocationGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocationGetterMockRecorder
+}
+
+// MockLocationGetterMockRecorder is the mock recorder for MockLocationGetter.
+type MockLocationGetterMockRecorder struct {
+	mock *MockLocationGetter
+}
+
+// NewMockLocationGetter creates a new mock instance.
+func NewMockLocationGetter(ctrl *gomock.Controller) *MockLocationGetter {
+	mock := &MockLocationGetter{ctrl: ctrl}
+	mock.recorder = &MockLocationGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocationGetter) EXPECT() *MockLocationGetterMockRecorder {
+	return m.recorder
+}
+
+// Location mocks base method.
+func (m *MockLocationGetter) Location(ctx context.Context, id uuid.UUID) (planner.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location", ctx, id)
+	ret0, _ := ret[0].(planner.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockLocationGetterMockRecorder) Location(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockLocationGetter)(nil).Location), ctx, id)
+}
+
 // MockRepository is a mock of Repository interface.
 type MockRepository struct {
 	ctrl     *gomock.Controller
@@ -41,6 +79,135 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AuditEntries mocks base method.
+func (m *MockRepository) AuditEntries(ctx context.Context, filter planner.AuditFilter) ([]planner.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditEntries", ctx, filter)
+	ret0, _ := ret[0].([]planner.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditEntries indicates an expected call of AuditEntries.
+func (mr *MockRepositoryMockRecorder) AuditEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditEntries", reflect.TypeOf((*MockRepository)(nil).AuditEntries), ctx, filter)
+}
+
+// Availabilities mocks base method.
+func (m *MockRepository) Availabilities(ctx context.Context, filter planner.AvailabilityFilter) ([]planner.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Availabilities", ctx, filter)
+	ret0, _ := ret[0].([]planner.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Availabilities indicates an expected call of Availabilities.
+func (mr *MockRepositoryMockRecorder) Availabilities(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Availabilities", reflect.TypeOf((*MockRepository)(nil).Availabilities), ctx, filter)
+}
+
+// Availability mocks base method.
+func (m *MockRepository) Availability(ctx context.Context, id uuid.UUID) (planner.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Availability", ctx, id)
+	ret0, _ := ret[0].(planner.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Availability indicates an expected call of Availability.
+func (mr *MockRepositoryMockRecorder) Availability(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Availability", reflect.TypeOf((*MockRepository)(nil).Availability), ctx, id)
+}
+
+// ClaimShift mocks base method.
+func (m *MockRepository) ClaimShift(ctx context.Context, id, workerID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimShift", ctx, id, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimShift indicates an expected call of ClaimShift.
+func (mr *MockRepositoryMockRecorder) ClaimShift(ctx, id, workerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimShift", reflect.TypeOf((*MockRepository)(nil).ClaimShift), ctx, id, workerID)
+}
+
+// CreateAuditEntry mocks base method.
+func (m *MockRepository) CreateAuditEntry(ctx context.Context, entry planner.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockRepositoryMockRecorder) CreateAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockRepository)(nil).CreateAuditEntry), ctx, entry)
+}
+
+// CreateAvailability mocks base method.
+func (m *MockRepository) CreateAvailability(ctx context.Context, availability planner.Availability) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAvailability", ctx, availability)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAvailability indicates an expected call of CreateAvailability.
+func (mr *MockRepositoryMockRecorder) CreateAvailability(ctx, availability any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAvailability", reflect.TypeOf((*MockRepository)(nil).CreateAvailability), ctx, availability)
+}
+
+// CreateLeave mocks base method.
+func (m *MockRepository) CreateLeave(ctx context.Context, leave planner.LeaveRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLeave", ctx, leave)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLeave indicates an expected call of CreateLeave.
+func (mr *MockRepositoryMockRecorder) CreateLeave(ctx, leave any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLeave", reflect.TypeOf((*MockRepository)(nil).CreateLeave), ctx, leave)
+}
+
+// CreateLocation mocks base method.
+func (m *MockRepository) CreateLocation(ctx context.Context, location planner.Location) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLocation", ctx, location)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLocation indicates an expected call of CreateLocation.
+func (mr *MockRepositoryMockRecorder) CreateLocation(ctx, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLocation", reflect.TypeOf((*MockRepository)(nil).CreateLocation), ctx, location)
+}
+
+// CreatePattern mocks base method.
+func (m *MockRepository) CreatePattern(ctx context.Context, pattern planner.ShiftPattern) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePattern", ctx, pattern)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePattern indicates an expected call of CreatePattern.
+func (mr *MockRepositoryMockRecorder) CreatePattern(ctx, pattern any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePattern", reflect.TypeOf((*MockRepository)(nil).CreatePattern), ctx, pattern)
+}
+
 // CreateShift mocks base method.
 func (m *MockRepository) CreateShift(ctx context.Context, shift planner.Shift) error {
 	m.ctrl.T.Helper()
@@ -55,6 +222,34 @@ func (mr *MockRepositoryMockRecorder) CreateShift(ctx, shift any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShift", reflect.TypeOf((*MockRepository)(nil).CreateShift), ctx, shift)
 }
 
+// CreateSwap mocks base method.
+func (m *MockRepository) CreateSwap(ctx context.Context, swap planner.SwapRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSwap", ctx, swap)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSwap indicates an expected call of CreateSwap.
+func (mr *MockRepositoryMockRecorder) CreateSwap(ctx, swap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSwap", reflect.TypeOf((*MockRepository)(nil).CreateSwap), ctx, swap)
+}
+
+// CreateUnavailability mocks base method.
+func (m *MockRepository) CreateUnavailability(ctx context.Context, unavailability planner.Unavailability) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUnavailability", ctx, unavailability)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUnavailability indicates an expected call of CreateUnavailability.
+func (mr *MockRepositoryMockRecorder) CreateUnavailability(ctx, unavailability any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUnavailability", reflect.TypeOf((*MockRepository)(nil).CreateUnavailability), ctx, unavailability)
+}
+
 // CreateWorker mocks base method.
 func (m *MockRepository) CreateWorker(ctx context.Context, worker planner.Worker) error {
 	m.ctrl.T.Helper()
@@ -69,6 +264,309 @@ func (mr *MockRepositoryMockRecorder) CreateWorker(ctx, worker any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorker", reflect.TypeOf((*MockRepository)(nil).CreateWorker), ctx, worker)
 }
 
+// DailyHours mocks base method.
+func (m *MockRepository) DailyHours(ctx context.Context, filter planner.ShiftsFilter) ([]planner.DailyHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DailyHours", ctx, filter)
+	ret0, _ := ret[0].([]planner.DailyHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DailyHours indicates an expected call of DailyHours.
+func (mr *MockRepositoryMockRecorder) DailyHours(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DailyHours", reflect.TypeOf((*MockRepository)(nil).DailyHours), ctx, filter)
+}
+
+// DeleteAvailabilities mocks base method.
+func (m *MockRepository) DeleteAvailabilities(ctx context.Context, filter planner.AvailabilityFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailabilities", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvailabilities indicates an expected call of DeleteAvailabilities.
+func (mr *MockRepositoryMockRecorder) DeleteAvailabilities(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailabilities", reflect.TypeOf((*MockRepository)(nil).DeleteAvailabilities), ctx, filter)
+}
+
+// DeleteAvailability mocks base method.
+func (m *MockRepository) DeleteAvailability(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailability", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvailability indicates an expected call of DeleteAvailability.
+func (mr *MockRepositoryMockRecorder) DeleteAvailability(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailability", reflect.TypeOf((*MockRepository)(nil).DeleteAvailability), ctx, id)
+}
+
+// DeleteLeaves mocks base method.
+func (m *MockRepository) DeleteLeaves(ctx context.Context, filter planner.LeavesFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLeaves", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLeaves indicates an expected call of DeleteLeaves.
+func (mr *MockRepositoryMockRecorder) DeleteLeaves(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLeaves", reflect.TypeOf((*MockRepository)(nil).DeleteLeaves), ctx, filter)
+}
+
+// DeleteLocation mocks base method.
+func (m *MockRepository) DeleteLocation(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLocation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLocation indicates an expected call of DeleteLocation.
+func (mr *MockRepositoryMockRecorder) DeleteLocation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLocation", reflect.TypeOf((*MockRepository)(nil).DeleteLocation), ctx, id)
+}
+
+// DeletePattern mocks base method.
+func (m *MockRepository) DeletePattern(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePattern", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePattern indicates an expected call of DeletePattern.
+func (mr *MockRepositoryMockRecorder) DeletePattern(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePattern", reflect.TypeOf((*MockRepository)(nil).DeletePattern), ctx, id)
+}
+
+// DeletePatterns mocks base method.
+func (m *MockRepository) DeletePatterns(ctx context.Context, filter planner.PatternsFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePatterns", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePatterns indicates an expected call of DeletePatterns.
+func (mr *MockRepositoryMockRecorder) DeletePatterns(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePatterns", reflect.TypeOf((*MockRepository)(nil).DeletePatterns), ctx, filter)
+}
+
+// DeleteShift mocks base method.
+func (m *MockRepository) DeleteShift(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShift", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShift indicates an expected call of DeleteShift.
+func (mr *MockRepositoryMockRecorder) DeleteShift(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShift", reflect.TypeOf((*MockRepository)(nil).DeleteShift), ctx, id)
+}
+
+// DeleteShifts mocks base method.
+func (m *MockRepository) DeleteShifts(ctx context.Context, filter planner.ShiftsFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShifts", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShifts indicates an expected call of DeleteShifts.
+func (mr *MockRepositoryMockRecorder) DeleteShifts(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShifts", reflect.TypeOf((*MockRepository)(nil).DeleteShifts), ctx, filter)
+}
+
+// DeleteSwaps mocks base method.
+func (m *MockRepository) DeleteSwaps(ctx context.Context, filter planner.SwapsFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSwaps", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSwaps indicates an expected call of DeleteSwaps.
+func (mr *MockRepositoryMockRecorder) DeleteSwaps(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSwaps", reflect.TypeOf((*MockRepository)(nil).DeleteSwaps), ctx, filter)
+}
+
+// DeleteUnavailabilities mocks base method.
+func (m *MockRepository) DeleteUnavailabilities(ctx context.Context, filter planner.UnavailabilityFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnavailabilities", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnavailabilities indicates an expected call of DeleteUnavailabilities.
+func (mr *MockRepositoryMockRecorder) DeleteUnavailabilities(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnavailabilities", reflect.TypeOf((*MockRepository)(nil).DeleteUnavailabilities), ctx, filter)
+}
+
+// DeleteUnavailability mocks base method.
+func (m *MockRepository) DeleteUnavailability(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnavailability", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnavailability indicates an expected call of DeleteUnavailability.
+func (mr *MockRepositoryMockRecorder) DeleteUnavailability(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnavailability", reflect.TypeOf((*MockRepository)(nil).DeleteUnavailability), ctx, id)
+}
+
+// DeleteWorker mocks base method.
+func (m *MockRepository) DeleteWorker(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorker", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorker indicates an expected call of DeleteWorker.
+func (mr *MockRepositoryMockRecorder) DeleteWorker(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorker", reflect.TypeOf((*MockRepository)(nil).DeleteWorker), ctx, id)
+}
+
+// Leave mocks base method.
+func (m *MockRepository) Leave(ctx context.Context, id uuid.UUID) (planner.LeaveRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Leave", ctx, id)
+	ret0, _ := ret[0].(planner.LeaveRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Leave indicates an expected call of Leave.
+func (mr *MockRepositoryMockRecorder) Leave(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Leave", reflect.TypeOf((*MockRepository)(nil).Leave), ctx, id)
+}
+
+// Leaves mocks base method.
+func (m *MockRepository) Leaves(ctx context.Context, filter planner.LeavesFilter) ([]planner.LeaveRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Leaves", ctx, filter)
+	ret0, _ := ret[0].([]planner.LeaveRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Leaves indicates an expected call of Leaves.
+func (mr *MockRepositoryMockRecorder) Leaves(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Leaves", reflect.TypeOf((*MockRepository)(nil).Leaves), ctx, filter)
+}
+
+// Location mocks base method.
+func (m *MockRepository) Location(ctx context.Context, id uuid.UUID) (planner.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location", ctx, id)
+	ret0, _ := ret[0].(planner.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockRepositoryMockRecorder) Location(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockRepository)(nil).Location), ctx, id)
+}
+
+// Locations mocks base method.
+func (m *MockRepository) Locations(ctx context.Context) ([]planner.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Locations", ctx)
+	ret0, _ := ret[0].([]planner.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Locations indicates an expected call of Locations.
+func (mr *MockRepositoryMockRecorder) Locations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Locations", reflect.TypeOf((*MockRepository)(nil).Locations), ctx)
+}
+
+// Pattern mocks base method.
+func (m *MockRepository) Pattern(ctx context.Context, id uuid.UUID) (planner.ShiftPattern, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pattern", ctx, id)
+	ret0, _ := ret[0].(planner.ShiftPattern)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pattern indicates an expected call of Pattern.
+func (mr *MockRepositoryMockRecorder) Pattern(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pattern", reflect.TypeOf((*MockRepository)(nil).Pattern), ctx, id)
+}
+
+// Patterns mocks base method.
+func (m *MockRepository) Patterns(ctx context.Context, filter planner.PatternsFilter) ([]planner.ShiftPattern, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patterns", ctx, filter)
+	ret0, _ := ret[0].([]planner.ShiftPattern)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patterns indicates an expected call of Patterns.
+func (mr *MockRepositoryMockRecorder) Patterns(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patterns", reflect.TypeOf((*MockRepository)(nil).Patterns), ctx, filter)
+}
+
+// Revisions mocks base method.
+func (m *MockRepository) Revisions(ctx context.Context, entity string, ids []uuid.UUID) ([]planner.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revisions", ctx, entity, ids)
+	ret0, _ := ret[0].([]planner.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revisions indicates an expected call of Revisions.
+func (mr *MockRepositoryMockRecorder) Revisions(ctx, entity, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockRepository)(nil).Revisions), ctx, entity, ids)
+}
+
+// Shift mocks base method.
+func (m *MockRepository) Shift(ctx context.Context, id uuid.UUID) (planner.Shift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shift", ctx, id)
+	ret0, _ := ret[0].(planner.Shift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Shift indicates an expected call of Shift.
+func (mr *MockRepositoryMockRecorder) Shift(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shift", reflect.TypeOf((*MockRepository)(nil).Shift), ctx, id)
+}
+
 // Shifts mocks base method.
 func (m *MockRepository) Shifts(ctx context.Context, filter planner.ShiftsFilter) ([]planner.Shift, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +582,36 @@ func (mr *MockRepositoryMockRecorder) Shifts(ctx, filter any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shifts", reflect.TypeOf((*MockRepository)(nil).Shifts), ctx, filter)
 }
 
+// Swap mocks base method.
+func (m *MockRepository) Swap(ctx context.Context, id uuid.UUID) (planner.SwapRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Swap", ctx, id)
+	ret0, _ := ret[0].(planner.SwapRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Swap indicates an expected call of Swap.
+func (mr *MockRepositoryMockRecorder) Swap(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Swap", reflect.TypeOf((*MockRepository)(nil).Swap), ctx, id)
+}
+
+// Swaps mocks base method.
+func (m *MockRepository) Swaps(ctx context.Context, filter planner.SwapsFilter) ([]planner.SwapRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Swaps", ctx, filter)
+	ret0, _ := ret[0].([]planner.SwapRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Swaps indicates an expected call of Swaps.
+func (mr *MockRepositoryMockRecorder) Swaps(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Swaps", reflect.TypeOf((*MockRepository)(nil).Swaps), ctx, filter)
+}
+
 // Transaction mocks base method.
 func (m *MockRepository) Transaction(ctx context.Context, action func(planner.Repository) error) error {
 	m.ctrl.T.Helper()
@@ -98,6 +626,134 @@ func (mr *MockRepositoryMockRecorder) Transaction(ctx, action any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockRepository)(nil).Transaction), ctx, action)
 }
 
+// Unavailabilities mocks base method.
+func (m *MockRepository) Unavailabilities(ctx context.Context, filter planner.UnavailabilityFilter) ([]planner.Unavailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unavailabilities", ctx, filter)
+	ret0, _ := ret[0].([]planner.Unavailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unavailabilities indicates an expected call of Unavailabilities.
+func (mr *MockRepositoryMockRecorder) Unavailabilities(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unavailabilities", reflect.TypeOf((*MockRepository)(nil).Unavailabilities), ctx, filter)
+}
+
+// Unavailability mocks base method.
+func (m *MockRepository) Unavailability(ctx context.Context, id uuid.UUID) (planner.Unavailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unavailability", ctx, id)
+	ret0, _ := ret[0].(planner.Unavailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unavailability indicates an expected call of Unavailability.
+func (mr *MockRepositoryMockRecorder) Unavailability(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unavailability", reflect.TypeOf((*MockRepository)(nil).Unavailability), ctx, id)
+}
+
+// UpdateAvailability mocks base method.
+func (m *MockRepository) UpdateAvailability(ctx context.Context, availability planner.Availability) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAvailability", ctx, availability)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAvailability indicates an expected call of UpdateAvailability.
+func (mr *MockRepositoryMockRecorder) UpdateAvailability(ctx, availability any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAvailability", reflect.TypeOf((*MockRepository)(nil).UpdateAvailability), ctx, availability)
+}
+
+// UpdateLeaveStatus mocks base method.
+func (m *MockRepository) UpdateLeaveStatus(ctx context.Context, id uuid.UUID, from, to planner.LeaveStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLeaveStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLeaveStatus indicates an expected call of UpdateLeaveStatus.
+func (mr *MockRepositoryMockRecorder) UpdateLeaveStatus(ctx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLeaveStatus", reflect.TypeOf((*MockRepository)(nil).UpdateLeaveStatus), ctx, id, from, to)
+}
+
+// UpdateLocation mocks base method.
+func (m *MockRepository) UpdateLocation(ctx context.Context, location planner.Location) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocation", ctx, location)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLocation indicates an expected call of UpdateLocation.
+func (mr *MockRepositoryMockRecorder) UpdateLocation(ctx, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocation", reflect.TypeOf((*MockRepository)(nil).UpdateLocation), ctx, location)
+}
+
+// UpdateShift mocks base method.
+func (m *MockRepository) UpdateShift(ctx context.Context, shift planner.Shift) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShift", ctx, shift)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShift indicates an expected call of UpdateShift.
+func (mr *MockRepositoryMockRecorder) UpdateShift(ctx, shift any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShift", reflect.TypeOf((*MockRepository)(nil).UpdateShift), ctx, shift)
+}
+
+// UpdateSwapStatus mocks base method.
+func (m *MockRepository) UpdateSwapStatus(ctx context.Context, id uuid.UUID, from, to planner.SwapStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSwapStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSwapStatus indicates an expected call of UpdateSwapStatus.
+func (mr *MockRepositoryMockRecorder) UpdateSwapStatus(ctx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSwapStatus", reflect.TypeOf((*MockRepository)(nil).UpdateSwapStatus), ctx, id, from, to)
+}
+
+// UpdateUnavailability mocks base method.
+func (m *MockRepository) UpdateUnavailability(ctx context.Context, unavailability planner.Unavailability) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUnavailability", ctx, unavailability)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUnavailability indicates an expected call of UpdateUnavailability.
+func (mr *MockRepositoryMockRecorder) UpdateUnavailability(ctx, unavailability any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUnavailability", reflect.TypeOf((*MockRepository)(nil).UpdateUnavailability), ctx, unavailability)
+}
+
+// UpdateWorker mocks base method.
+func (m *MockRepository) UpdateWorker(ctx context.Context, worker planner.Worker) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorker", ctx, worker)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorker indicates an expected call of UpdateWorker.
+func (mr *MockRepositoryMockRecorder) UpdateWorker(ctx, worker any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorker", reflect.TypeOf((*MockRepository)(nil).UpdateWorker), ctx, worker)
+}
+
 // Worker mocks base method.
 func (m *MockRepository) Worker(ctx context.Context, id uuid.UUID) (planner.Worker, error) {
 	m.ctrl.T.Helper()
@@ -5,35 +5,88 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/sp4rd4/wrkpln/planner"
 	driver "gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DB struct {
 	*gorm.DB
 }
 
-func New(dbFilepath, schema string) (DB, error) {
+// New opens the database and brings its schema up to date.
+// Schema file describes the latest schema and is applied as is to a new database,
+// existing database is upgraded with migrations from migrationsDir first.
+// Applied migrations are tracked with user_version pragma.
+// Records are kept per tenant, see scopeTenant.
+func New(dbFilepath, schema, migrationsDir string) (DB, error) {
 	db, err := gorm.Open(driver.Open(dbFilepath))
 	if err != nil {
 		return DB{}, fmt.Errorf("open db: %w", err)
 	}
+	if err := scopeTenant(db); err != nil {
+		return DB{}, err
+	}
+	migrations, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+	if err != nil {
+		return DB{}, fmt.Errorf("list db migrations: %w", err)
+	}
+	sort.Strings(migrations)
+
+	if db.Migrator().HasTable("workers") {
+		if err := migrate(db, migrations); err != nil {
+			return DB{}, err
+		}
+	} else {
+		res := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(migrations)))
+		if res.Error != nil {
+			return DB{}, fmt.Errorf("set db version: %w", res.Error)
+		}
+	}
+
 	schemaSQL, err := os.ReadFile(schema)
 	if err != nil {
 		return DB{}, fmt.Errorf("read db schema: %w", err)
 	}
 	res := db.Exec(string(schemaSQL))
 	if res.Error != nil {
-		return DB{}, fmt.Errorf("load db schema: %w", err)
+		return DB{}, fmt.Errorf("load db schema: %w", res.Error)
 	}
 	return DB{DB: db}, nil
 }
 
+func migrate(db *gorm.DB, migrations []string) error {
+	var version int
+	res := db.Raw("PRAGMA user_version").Scan(&version)
+	if res.Error != nil {
+		return fmt.Errorf("get db version: %w", res.Error)
+	}
+	for i := version; i < len(migrations); i++ {
+		migrationSQL, err := os.ReadFile(migrations[i])
+		if err != nil {
+			return fmt.Errorf("read db migration: %w", err)
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if res := tx.Exec(string(migrationSQL)); res.Error != nil {
+				return res.Error
+			}
+			return tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)).Error
+		})
+		if err != nil {
+			return fmt.Errorf("apply db migration %s: %w", filepath.Base(migrations[i]), err)
+		}
+	}
+	return nil
+}
+
 func (db DB) CreateWorker(ctx context.Context, worker planner.Worker) error {
-	res := db.WithContext(ctx).Create(worker)
+	res := db.WithContext(ctx).Create(&worker)
 	if res.Error != nil {
 		return fmt.Errorf("create worker: %w", res.Error)
 	}
@@ -59,6 +112,28 @@ func (db DB) Workers(ctx context.Context, filter planner.WorkersFilter) ([]plann
 	if filter.Name != nil {
 		query = query.Where("name LIKE ?", "%"+*filter.Name+"%")
 	}
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+	for _, skill := range filter.Skills {
+		query = query.Where("EXISTS (SELECT 1 FROM json_each(workers.skills) WHERE value = ?)", skill)
+	}
+
+	var columns []string
+	var after []any
+	switch filter.Sort.Field {
+	case planner.SortByID:
+		columns = []string{"id"}
+		if filter.After != nil {
+			after = []any{filter.After.ID}
+		}
+	default:
+		columns = []string{"name", "id"}
+		if filter.After != nil {
+			after = []any{filter.After.Name, filter.After.ID}
+		}
+	}
+	query = page(query, columns, filter.Sort.Desc, after, filter.Limit)
 
 	res := query.Find(&workers)
 	if res.Error != nil {
@@ -67,23 +142,75 @@ func (db DB) Workers(ctx context.Context, filter planner.WorkersFilter) ([]plann
 	return workers, nil
 }
 
+func (db DB) UpdateWorker(ctx context.Context, worker planner.Worker) error {
+	res := db.WithContext(ctx).Model(&worker).Select("*").Updates(worker)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("update worker: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteWorker(ctx context.Context, id uuid.UUID) error {
+	res := db.WithContext(ctx).Delete(&planner.Worker{}, "id = ?", id)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("delete worker: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
 func (db DB) CreateShift(ctx context.Context, shift planner.Shift) error {
-	res := db.WithContext(ctx).Create(shift)
+	res := db.WithContext(ctx).Create(&shift)
 	if res.Error != nil {
 		return fmt.Errorf("create shift: %w", res.Error)
 	}
 	return nil
 }
 
+func (db DB) Shift(ctx context.Context, id uuid.UUID) (planner.Shift, error) {
+	shift := planner.Shift{}
+	res := db.WithContext(ctx).Take(&shift, "id = ?", id)
+	switch {
+	case errors.Is(res.Error, gorm.ErrRecordNotFound):
+		return planner.Shift{}, planner.ErrNoRecord
+	case res.Error != nil:
+		return planner.Shift{}, fmt.Errorf("get shift: %w", res.Error)
+	default:
+		return shift, nil
+	}
+}
+
+// openWorkerID sorts open shifts first, as NULL can't be compared in keyset conditions,
+// shifts_date_open_worker_id_idx is built on the same expression.
+const openWorkerID = "COALESCE(worker_id, '00000000-0000-0000-0000-000000000000')"
+
 func (db DB) Shifts(ctx context.Context, filter planner.ShiftsFilter) ([]planner.Shift, error) {
 	shifts := []planner.Shift{}
-	query := db.WithContext(ctx)
-	if filter.Date != nil {
-		query = query.Where("date = ?", *filter.Date)
-	}
-	if filter.WorkerID != nil {
-		query = query.Where("worker_id = ?", *filter.WorkerID)
+	query := db.shiftsQuery(ctx, filter)
+
+	var columns []string
+	var after []any
+	switch filter.Sort.Field {
+	case planner.SortByWorker:
+		columns = []string{openWorkerID, "date", "id"}
+		if filter.After != nil {
+			after = []any{filter.After.WorkerID, filter.After.Date, filter.After.ID}
+		}
+	default:
+		// ordering matches shifts_date_open_worker_id_idx
+		columns = []string{"date", openWorkerID, "id"}
+		if filter.After != nil {
+			after = []any{filter.After.Date, filter.After.WorkerID, filter.After.ID}
+		}
 	}
+	query = page(query, columns, filter.Sort.Desc, after, filter.Limit)
 
 	res := query.Find(&shifts)
 	if res.Error != nil {
@@ -92,9 +219,576 @@ func (db DB) Shifts(ctx context.Context, filter planner.ShiftsFilter) ([]planner
 	return shifts, nil
 }
 
+func (db DB) UpdateShift(ctx context.Context, shift planner.Shift) error {
+	res := db.WithContext(ctx).Model(&shift).Select("*").Updates(shift)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("update shift: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteShift(ctx context.Context, id uuid.UUID) error {
+	res := db.WithContext(ctx).Delete(&planner.Shift{}, "id = ?", id)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("delete shift: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteShifts(ctx context.Context, filter planner.ShiftsFilter) error {
+	res := db.shiftsQuery(ctx, filter).Delete(&planner.Shift{})
+	if res.Error != nil {
+		return fmt.Errorf("delete shifts: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) ClaimShift(ctx context.Context, id, workerID uuid.UUID) error {
+	res := db.WithContext(ctx).Model(&planner.Shift{}).
+		Where("id = ? AND worker_id IS NULL", id).
+		Update("worker_id", workerID)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("claim shift: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrShiftTaken
+	default:
+		return nil
+	}
+}
+
+func (db DB) DailyHours(ctx context.Context, filter planner.ShiftsFilter) ([]planner.DailyHours, error) {
+	hours := []planner.DailyHours{}
+	// overnight shifts end on the next day
+	res := db.shiftsQuery(ctx, filter).Model(&planner.Shift{}).
+		Select(
+			"worker_id, date, COUNT(*) AS shifts, " +
+				"SUM(CASE WHEN end_minute > start_minute THEN end_minute - start_minute " +
+				"ELSE end_minute + 1440 - start_minute END) AS minutes",
+		).
+		Where("worker_id IS NOT NULL").
+		Group("worker_id, date").
+		Order("worker_id, date").
+		Scan(&hours)
+	if res.Error != nil {
+		return nil, fmt.Errorf("sum daily hours: %w", res.Error)
+	}
+	return hours, nil
+}
+
+func (db DB) CreatePattern(ctx context.Context, pattern planner.ShiftPattern) error {
+	res := db.WithContext(ctx).Create(&pattern)
+	if res.Error != nil {
+		return fmt.Errorf("create pattern: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) Pattern(ctx context.Context, id uuid.UUID) (planner.ShiftPattern, error) {
+	pattern := planner.ShiftPattern{}
+	res := db.WithContext(ctx).Take(&pattern, "id = ?", id)
+	switch {
+	case errors.Is(res.Error, gorm.ErrRecordNotFound):
+		return planner.ShiftPattern{}, planner.ErrNoRecord
+	case res.Error != nil:
+		return planner.ShiftPattern{}, fmt.Errorf("get pattern: %w", res.Error)
+	default:
+		return pattern, nil
+	}
+}
+
+func (db DB) Patterns(ctx context.Context, filter planner.PatternsFilter) ([]planner.ShiftPattern, error) {
+	patterns := []planner.ShiftPattern{}
+	res := db.patternsQuery(ctx, filter).Order("id").Find(&patterns)
+	if res.Error != nil {
+		return nil, fmt.Errorf("list patterns: %w", res.Error)
+	}
+	return patterns, nil
+}
+
+func (db DB) DeletePattern(ctx context.Context, id uuid.UUID) error {
+	res := db.WithContext(ctx).Delete(&planner.ShiftPattern{}, "id = ?", id)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("delete pattern: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeletePatterns(ctx context.Context, filter planner.PatternsFilter) error {
+	res := db.patternsQuery(ctx, filter).Delete(&planner.ShiftPattern{})
+	if res.Error != nil {
+		return fmt.Errorf("delete patterns: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) CreateAvailability(ctx context.Context, availability planner.Availability) error {
+	res := db.WithContext(ctx).Create(&availability)
+	if res.Error != nil {
+		return fmt.Errorf("create availability: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) Availability(ctx context.Context, id uuid.UUID) (planner.Availability, error) {
+	availability := planner.Availability{}
+	res := db.WithContext(ctx).Take(&availability, "id = ?", id)
+	switch {
+	case errors.Is(res.Error, gorm.ErrRecordNotFound):
+		return planner.Availability{}, planner.ErrNoRecord
+	case res.Error != nil:
+		return planner.Availability{}, fmt.Errorf("get availability: %w", res.Error)
+	default:
+		return availability, nil
+	}
+}
+
+func (db DB) Availabilities(ctx context.Context, filter planner.AvailabilityFilter) ([]planner.Availability, error) {
+	availabilities := []planner.Availability{}
+	res := db.availabilitiesQuery(ctx, filter).Order("id").Find(&availabilities)
+	if res.Error != nil {
+		return nil, fmt.Errorf("list availabilities: %w", res.Error)
+	}
+	return availabilities, nil
+}
+
+func (db DB) UpdateAvailability(ctx context.Context, availability planner.Availability) error {
+	res := db.WithContext(ctx).Model(&availability).Select("*").Updates(availability)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("update availability: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteAvailability(ctx context.Context, id uuid.UUID) error {
+	res := db.WithContext(ctx).Delete(&planner.Availability{}, "id = ?", id)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("delete availability: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteAvailabilities(ctx context.Context, filter planner.AvailabilityFilter) error {
+	res := db.availabilitiesQuery(ctx, filter).Delete(&planner.Availability{})
+	if res.Error != nil {
+		return fmt.Errorf("delete availabilities: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) CreateUnavailability(ctx context.Context, unavailability planner.Unavailability) error {
+	res := db.WithContext(ctx).Create(&unavailability)
+	if res.Error != nil {
+		return fmt.Errorf("create unavailability: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) Unavailability(ctx context.Context, id uuid.UUID) (planner.Unavailability, error) {
+	unavailability := planner.Unavailability{}
+	res := db.WithContext(ctx).Take(&unavailability, "id = ?", id)
+	switch {
+	case errors.Is(res.Error, gorm.ErrRecordNotFound):
+		return planner.Unavailability{}, planner.ErrNoRecord
+	case res.Error != nil:
+		return planner.Unavailability{}, fmt.Errorf("get unavailability: %w", res.Error)
+	default:
+		return unavailability, nil
+	}
+}
+
+func (db DB) Unavailabilities(ctx context.Context, filter planner.UnavailabilityFilter) ([]planner.Unavailability, error) {
+	unavailabilities := []planner.Unavailability{}
+	res := db.unavailabilitiesQuery(ctx, filter).Order("starts_at, id").Find(&unavailabilities)
+	if res.Error != nil {
+		return nil, fmt.Errorf("list unavailabilities: %w", res.Error)
+	}
+	return unavailabilities, nil
+}
+
+func (db DB) UpdateUnavailability(ctx context.Context, unavailability planner.Unavailability) error {
+	res := db.WithContext(ctx).Model(&unavailability).Select("*").Updates(unavailability)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("update unavailability: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteUnavailability(ctx context.Context, id uuid.UUID) error {
+	res := db.WithContext(ctx).Delete(&planner.Unavailability{}, "id = ?", id)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("delete unavailability: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteUnavailabilities(ctx context.Context, filter planner.UnavailabilityFilter) error {
+	res := db.unavailabilitiesQuery(ctx, filter).Delete(&planner.Unavailability{})
+	if res.Error != nil {
+		return fmt.Errorf("delete unavailabilities: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) CreateLeave(ctx context.Context, leave planner.LeaveRequest) error {
+	res := db.WithContext(ctx).Create(&leave)
+	if res.Error != nil {
+		return fmt.Errorf("create leave: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) Leave(ctx context.Context, id uuid.UUID) (planner.LeaveRequest, error) {
+	leave := planner.LeaveRequest{}
+	res := db.WithContext(ctx).Take(&leave, "id = ?", id)
+	switch {
+	case errors.Is(res.Error, gorm.ErrRecordNotFound):
+		return planner.LeaveRequest{}, planner.ErrNoRecord
+	case res.Error != nil:
+		return planner.LeaveRequest{}, fmt.Errorf("get leave: %w", res.Error)
+	default:
+		return leave, nil
+	}
+}
+
+func (db DB) Leaves(ctx context.Context, filter planner.LeavesFilter) ([]planner.LeaveRequest, error) {
+	leaves := []planner.LeaveRequest{}
+	res := db.leavesQuery(ctx, filter).Order("starts_at, id").Find(&leaves)
+	if res.Error != nil {
+		return nil, fmt.Errorf("list leaves: %w", res.Error)
+	}
+	return leaves, nil
+}
+
+func (db DB) UpdateLeaveStatus(ctx context.Context, id uuid.UUID, from, to planner.LeaveStatus) error {
+	res := db.WithContext(ctx).Model(&planner.LeaveRequest{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("update leave status: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrInvalidTransition
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteLeaves(ctx context.Context, filter planner.LeavesFilter) error {
+	res := db.leavesQuery(ctx, filter).Delete(&planner.LeaveRequest{})
+	if res.Error != nil {
+		return fmt.Errorf("delete leaves: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) CreateSwap(ctx context.Context, swap planner.SwapRequest) error {
+	res := db.WithContext(ctx).Create(&swap)
+	if res.Error != nil {
+		return fmt.Errorf("create swap: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) Swap(ctx context.Context, id uuid.UUID) (planner.SwapRequest, error) {
+	swap := planner.SwapRequest{}
+	res := db.WithContext(ctx).Take(&swap, "id = ?", id)
+	switch {
+	case errors.Is(res.Error, gorm.ErrRecordNotFound):
+		return planner.SwapRequest{}, planner.ErrNoRecord
+	case res.Error != nil:
+		return planner.SwapRequest{}, fmt.Errorf("get swap: %w", res.Error)
+	default:
+		return swap, nil
+	}
+}
+
+func (db DB) Swaps(ctx context.Context, filter planner.SwapsFilter) ([]planner.SwapRequest, error) {
+	swaps := []planner.SwapRequest{}
+	res := db.swapsQuery(ctx, filter).Order("id").Find(&swaps)
+	if res.Error != nil {
+		return nil, fmt.Errorf("list swaps: %w", res.Error)
+	}
+	return swaps, nil
+}
+
+func (db DB) UpdateSwapStatus(ctx context.Context, id uuid.UUID, from, to planner.SwapStatus) error {
+	res := db.WithContext(ctx).Model(&planner.SwapRequest{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("update swap status: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrInvalidTransition
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteSwaps(ctx context.Context, filter planner.SwapsFilter) error {
+	res := db.swapsQuery(ctx, filter).Delete(&planner.SwapRequest{})
+	if res.Error != nil {
+		return fmt.Errorf("delete swaps: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) CreateLocation(ctx context.Context, location planner.Location) error {
+	res := db.WithContext(ctx).Create(&location)
+	if res.Error != nil {
+		return fmt.Errorf("create location: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) Location(ctx context.Context, id uuid.UUID) (planner.Location, error) {
+	location := planner.Location{}
+	res := db.WithContext(ctx).Take(&location, "id = ?", id)
+	switch {
+	case errors.Is(res.Error, gorm.ErrRecordNotFound):
+		return planner.Location{}, planner.ErrNoRecord
+	case res.Error != nil:
+		return planner.Location{}, fmt.Errorf("get location: %w", res.Error)
+	default:
+		return location, nil
+	}
+}
+
+func (db DB) Locations(ctx context.Context) ([]planner.Location, error) {
+	locations := []planner.Location{}
+	res := db.WithContext(ctx).Order("name").Order("id").Find(&locations)
+	if res.Error != nil {
+		return nil, fmt.Errorf("list locations: %w", res.Error)
+	}
+	return locations, nil
+}
+
+func (db DB) UpdateLocation(ctx context.Context, location planner.Location) error {
+	res := db.WithContext(ctx).Model(&location).Select("*").Updates(location)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("update location: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) DeleteLocation(ctx context.Context, id uuid.UUID) error {
+	res := db.WithContext(ctx).Delete(&planner.Location{}, "id = ?", id)
+	switch {
+	case res.Error != nil:
+		return fmt.Errorf("delete location: %w", res.Error)
+	case res.RowsAffected == 0:
+		return planner.ErrNoRecord
+	default:
+		return nil
+	}
+}
+
+func (db DB) CreateAuditEntry(ctx context.Context, entry planner.AuditEntry) error {
+	res := db.WithContext(ctx).Create(&entry)
+	if res.Error != nil {
+		return fmt.Errorf("create audit entry: %w", res.Error)
+	}
+	return nil
+}
+
+func (db DB) AuditEntries(ctx context.Context, filter planner.AuditFilter) ([]planner.AuditEntry, error) {
+	entries := []planner.AuditEntry{}
+	query := db.WithContext(ctx)
+	if filter.Entity != nil {
+		query = query.Where("entity = ?", *filter.Entity)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Actor != nil {
+		query = query.Where("actor = ?", *filter.Actor)
+	}
+	if filter.From != nil {
+		query = query.Where("recorded_at >= ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		query = query.Where("recorded_at <= ?", filter.To.UTC())
+	}
+	var after []any
+	if filter.After != nil {
+		after = []any{filter.After.Date.UTC(), filter.After.ID}
+	}
+	// ordering matches audit_entries_tenant_id_recorded_at_idx
+	query = page(query, []string{"recorded_at", "id"}, false, after, filter.Limit)
+
+	res := query.Find(&entries)
+	if res.Error != nil {
+		return nil, fmt.Errorf("list audit entries: %w", res.Error)
+	}
+	return entries, nil
+}
+
+const maxQueryIDs = 10000
+
+func (db DB) Revisions(ctx context.Context, entity string, ids []uuid.UUID) ([]planner.Revision, error) {
+	revisions := []planner.Revision{}
+	// ids are queried in chunks below SQLite limit of query parameters
+	for len(ids) > 0 {
+		chunk := ids[:min(len(ids), maxQueryIDs)]
+		ids = ids[len(chunk):]
+
+		found := []planner.Revision{}
+		// bare recorded_at is taken from the row with MAX(recorded_at), unlike the aggregate
+		// it keeps column type, so it's scanned as time
+		res := db.WithContext(ctx).Model(&planner.AuditEntry{}).
+			Select("entity_id, recorded_at AS modified, MAX(recorded_at) AS last, COUNT(*) - 1 AS sequence").
+			Where("entity = ? AND entity_id IN ?", entity, chunk).
+			Group("entity_id").
+			Scan(&found)
+		if res.Error != nil {
+			return nil, fmt.Errorf("list revisions: %w", res.Error)
+		}
+		revisions = append(revisions, found...)
+	}
+	return revisions, nil
+}
+
 func (db DB) Transaction(ctx context.Context, action func(planner.Repository) error) error {
 	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		txDB := DB{DB: tx}
 		return action(txDB)
 	})
 }
+
+func (db DB) shiftsQuery(ctx context.Context, filter planner.ShiftsFilter) *gorm.DB {
+	query := db.WithContext(ctx)
+	if filter.Date != nil {
+		query = query.Where("date = ?", *filter.Date)
+	}
+	if filter.From != nil {
+		query = query.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("date <= ?", *filter.To)
+	}
+	if filter.WorkerID != nil {
+		query = query.Where("worker_id = ?", *filter.WorkerID)
+	}
+	if filter.Open {
+		query = query.Where("worker_id IS NULL")
+	}
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+	return query
+}
+
+func (db DB) patternsQuery(ctx context.Context, filter planner.PatternsFilter) *gorm.DB {
+	query := db.WithContext(ctx)
+	if filter.WorkerID != nil {
+		query = query.Where("worker_id = ?", *filter.WorkerID)
+	}
+	return query
+}
+
+func (db DB) availabilitiesQuery(ctx context.Context, filter planner.AvailabilityFilter) *gorm.DB {
+	query := db.WithContext(ctx)
+	if filter.WorkerID != nil {
+		query = query.Where("worker_id = ?", *filter.WorkerID)
+	}
+	return query
+}
+
+func (db DB) unavailabilitiesQuery(ctx context.Context, filter planner.UnavailabilityFilter) *gorm.DB {
+	query := db.WithContext(ctx)
+	if filter.WorkerID != nil {
+		query = query.Where("worker_id = ?", *filter.WorkerID)
+	}
+	// ranges are half-open, so touching ranges don't overlap
+	if filter.From != nil {
+		query = query.Where("ends_at > ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		query = query.Where("starts_at < ?", filter.To.UTC())
+	}
+	return query
+}
+
+func (db DB) leavesQuery(ctx context.Context, filter planner.LeavesFilter) *gorm.DB {
+	query := db.WithContext(ctx)
+	if filter.WorkerID != nil {
+		query = query.Where("worker_id = ?", *filter.WorkerID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("ends_at > ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		query = query.Where("starts_at < ?", filter.To.UTC())
+	}
+	return query
+}
+
+func (db DB) swapsQuery(ctx context.Context, filter planner.SwapsFilter) *gorm.DB {
+	query := db.WithContext(ctx)
+	if filter.WorkerID != nil {
+		query = query.Where("from_worker_id = ? OR to_worker_id = ?", *filter.WorkerID, *filter.WorkerID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	return query
+}
+
+// page applies keyset pagination: rows are ordered by columns
+// and only rows placed after the given column values are selected.
+func page(query *gorm.DB, columns []string, desc bool, after []any, limit int) *gorm.DB {
+	cols := strings.Join(columns, ", ")
+	if after != nil {
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(after)), ", ")
+		query = query.Where("("+cols+") "+op+" ("+placeholders+")", after...)
+	}
+	for _, col := range columns {
+		// expressions are ordered as is, not quoted as column names
+		column := clause.Column{Name: col, Raw: strings.ContainsRune(col, '(')}
+		query = query.Order(clause.OrderByColumn{Column: column, Desc: desc})
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	return query
+}
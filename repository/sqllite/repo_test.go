@@ -0,0 +1,402 @@
+package sqllite_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/repository/sqllite"
+)
+
+func newDB(t *testing.T) sqllite.DB {
+	t.Helper()
+	db, err := sqllite.New(
+		filepath.Join(t.TempDir(), "test.db"),
+		"../../db/schema.sql", "../../db/migrations",
+	)
+	require.NoError(t, err)
+	return db
+}
+
+func TestTenantIsolation(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	ctxA := planner.WithTenant(context.Background(), "a")
+	ctxB := planner.WithTenant(context.Background(), "b")
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+
+	location := planner.Location{ID: uuid.New(), Name: "North"}
+	worker := planner.Worker{ID: uuid.New(), Name: "Ann", LocationID: &location.ID}
+	shift := planner.Shift{ID: uuid.New(), WorkerID: &worker.ID, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	open := planner.Shift{ID: uuid.New(), Date: date, Start: planner.NewClock(16, 0), End: planner.NewClock(24, 0)}
+	require.NoError(t, db.CreateLocation(ctxA, location))
+	require.NoError(t, db.CreateWorker(ctxA, worker))
+	require.NoError(t, db.CreateShift(ctxA, shift))
+	require.NoError(t, db.CreateShift(ctxA, open))
+	other := planner.Worker{ID: uuid.New(), Name: "Ann"}
+	require.NoError(t, db.CreateWorker(ctxB, other))
+
+	t.Run("Reads", func(t *testing.T) {
+		_, err := db.Worker(ctxB, worker.ID)
+		assert.ErrorIs(t, err, planner.ErrNoRecord)
+		_, err = db.Shift(ctxB, shift.ID)
+		assert.ErrorIs(t, err, planner.ErrNoRecord)
+		_, err = db.Location(ctxB, location.ID)
+		assert.ErrorIs(t, err, planner.ErrNoRecord)
+
+		workers, err := db.Workers(ctxB, planner.WorkersFilter{Name: ptr("Ann")})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{other.ID}, workerIDs(workers))
+		workers, err = db.Workers(ctxA, planner.WorkersFilter{Name: ptr("Ann")})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{worker.ID}, workerIDs(workers))
+
+		shifts, err := db.Shifts(ctxB, planner.ShiftsFilter{Date: &date})
+		assert.NoError(t, err)
+		assert.Empty(t, shifts)
+		locations, err := db.Locations(ctxB)
+		assert.NoError(t, err)
+		assert.Empty(t, locations)
+	})
+
+	t.Run("Writes", func(t *testing.T) {
+		assert.ErrorIs(t, db.UpdateWorker(ctxB, planner.Worker{ID: worker.ID, Name: "Eve"}), planner.ErrNoRecord)
+		assert.ErrorIs(t, db.UpdateShift(ctxB, planner.Shift{ID: shift.ID, WorkerID: &other.ID, Date: date}), planner.ErrNoRecord)
+		assert.ErrorIs(t, db.ClaimShift(ctxB, open.ID, other.ID), planner.ErrShiftTaken)
+		assert.ErrorIs(t, db.DeleteWorker(ctxB, worker.ID), planner.ErrNoRecord)
+		assert.ErrorIs(t, db.DeleteLocation(ctxB, location.ID), planner.ErrNoRecord)
+		assert.NoError(t, db.DeleteShifts(ctxB, planner.ShiftsFilter{WorkerID: &worker.ID}))
+		err := db.Transaction(ctxB, func(repo planner.Repository) error {
+			return repo.DeleteShift(ctxB, shift.ID)
+		})
+		assert.ErrorIs(t, err, planner.ErrNoRecord)
+
+		stored, err := db.Worker(ctxA, worker.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Ann", stored.Name)
+		assert.Equal(t, "a", stored.TenantID)
+		storedShift, err := db.Shift(ctxA, shift.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, &worker.ID, storedShift.WorkerID)
+		storedOpen, err := db.Shift(ctxA, open.ID)
+		assert.NoError(t, err)
+		assert.True(t, storedOpen.Open())
+	})
+
+	t.Run("Update keeps tenant", func(t *testing.T) {
+		// records coming from requests have no tenant set
+		assert.NoError(t, db.UpdateWorker(ctxA, planner.Worker{ID: worker.ID, Name: "Ann Lee"}))
+		stored, err := db.Worker(ctxA, worker.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "a", stored.TenantID)
+	})
+}
+
+func TestNoTenant(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, db.CreateWorker(ctx, planner.Worker{ID: uuid.New(), Name: "Ann"}), sqllite.ErrNoTenant)
+	_, err := db.Workers(ctx, planner.WorkersFilter{})
+	assert.ErrorIs(t, err, sqllite.ErrNoTenant)
+	assert.ErrorIs(t, db.DeleteShift(ctx, uuid.New()), sqllite.ErrNoTenant)
+	_, err = db.DailyHours(ctx, planner.ShiftsFilter{})
+	assert.ErrorIs(t, err, sqllite.ErrNoTenant)
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	ctx := planner.WithActor(planner.WithTenant(context.Background(), "a"), "alice")
+	start := time.Now()
+
+	plan := planner.New(db)
+	worker, err := plan.CreateWorker(ctx, planner.Worker{Name: "Ann"})
+	require.NoError(t, err)
+	_, err = plan.PatchWorker(planner.WithActor(ctx, "bob"), worker.ID, planner.WorkerPatch{Name: ptr("Ann Lee")})
+	require.NoError(t, err)
+	require.NoError(t, plan.DeleteWorker(ctx, worker.ID))
+
+	entries, err := db.AuditEntries(ctx, planner.AuditFilter{EntityID: &worker.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"create", "update", "delete"}, []string{entries[0].Action, entries[1].Action, entries[2].Action})
+	assert.Equal(t, "bob", entries[1].Actor)
+	assert.JSONEq(t, `{"id":"`+worker.ID.String()+`","name":"Ann"}`, string(entries[1].Before))
+	assert.JSONEq(t, `{"id":"`+worker.ID.String()+`","name":"Ann Lee"}`, string(entries[1].After))
+	assert.Nil(t, entries[2].After)
+
+	entries, err = db.AuditEntries(ctx, planner.AuditFilter{Actor: ptr("alice"), Entity: ptr("worker"), From: &start, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "create", entries[0].Action)
+	after := planner.Cursor{Date: entries[0].Time, ID: entries[0].ID}
+	entries, err = db.AuditEntries(ctx, planner.AuditFilter{Actor: ptr("alice"), After: &after})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "delete", entries[0].Action)
+	entries, err = db.AuditEntries(ctx, planner.AuditFilter{To: &start})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	entries, err = db.AuditEntries(planner.WithTenant(ctx, "b"), planner.AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// entries can't be changed even bypassing the repository
+	assert.Error(t, db.Exec("UPDATE audit_entries SET actor = 'eve'").Error)
+	assert.Error(t, db.Exec("DELETE FROM audit_entries").Error)
+}
+
+func TestRevisions(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	ctx := planner.WithTenant(context.Background(), "a")
+	created := time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)
+	changed, unchanged, untracked := uuid.New(), uuid.New(), uuid.New()
+	entry := func(ctx context.Context, entity string, id uuid.UUID, action string, at time.Time) {
+		t.Helper()
+		require.NoError(t, db.CreateAuditEntry(ctx, planner.AuditEntry{
+			ID: uuid.New(), Time: at, Actor: "alice", Entity: entity, EntityID: id, Action: action,
+		}))
+	}
+	entry(ctx, "shift", changed, "create", created)
+	entry(ctx, "shift", changed, "update", created.Add(2*time.Hour))
+	entry(ctx, "shift", changed, "claim", created.Add(time.Hour))
+	entry(ctx, "shift", unchanged, "create", created)
+	// entries of other entities and tenants aren't counted
+	entry(ctx, "worker", unchanged, "update", created.Add(time.Hour))
+	entry(planner.WithTenant(ctx, "b"), "shift", unchanged, "update", created.Add(time.Hour))
+
+	revisions, err := db.Revisions(ctx, "shift", []uuid.UUID{changed, unchanged, untracked})
+	require.NoError(t, err)
+	byID := map[uuid.UUID]planner.Revision{}
+	for _, r := range revisions {
+		r.Modified = r.Modified.UTC()
+		byID[r.EntityID] = r
+	}
+	assert.Equal(t, map[uuid.UUID]planner.Revision{
+		changed:   {EntityID: changed, Modified: created.Add(2 * time.Hour), Sequence: 2},
+		unchanged: {EntityID: unchanged, Modified: created},
+	}, byID)
+
+	revisions, err = db.Revisions(ctx, "shift", nil)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
+func TestDeleteWorkerAudit(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	ctx := planner.WithActor(planner.WithTenant(context.Background(), "a"), "alice")
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+
+	plan := planner.New(db)
+	worker, err := plan.CreateWorker(ctx, planner.Worker{Name: "Ann"})
+	require.NoError(t, err)
+	other, err := plan.CreateWorker(ctx, planner.Worker{Name: "Bob"})
+	require.NoError(t, err)
+	shift, err := plan.CreateShift(ctx, planner.Shift{WorkerID: &worker.ID, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)})
+	require.NoError(t, err)
+	pattern, err := plan.CreatePattern(ctx, planner.ShiftPattern{
+		WorkerID: worker.ID, Kind: planner.PatternWeekly, Weekdays: planner.NewWeekdays(time.Monday),
+		Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0),
+	})
+	require.NoError(t, err)
+	availability, err := plan.CreateAvailability(ctx, planner.Availability{
+		WorkerID: worker.ID, Weekdays: planner.NewWeekdays(time.Monday), Start: planner.NewClock(6, 0), End: planner.NewClock(18, 0),
+	})
+	require.NoError(t, err)
+	unavailability, err := plan.CreateUnavailability(ctx, planner.Unavailability{
+		WorkerID: worker.ID, From: date.AddDate(0, 0, 1), To: date.AddDate(0, 0, 2),
+	})
+	require.NoError(t, err)
+	leave, err := plan.RequestLeave(ctx, planner.LeaveRequest{WorkerID: worker.ID, From: date.AddDate(0, 0, 7), To: date.AddDate(0, 0, 8)})
+	require.NoError(t, err)
+	swap, err := plan.RequestSwap(ctx, planner.SwapRequest{ShiftID: shift.ID, ToWorkerID: other.ID})
+	require.NoError(t, err)
+
+	require.NoError(t, plan.DeleteWorker(ctx, worker.ID))
+
+	deleted := map[string][]uuid.UUID{}
+	entries, err := db.AuditEntries(ctx, planner.AuditFilter{})
+	require.NoError(t, err)
+	for _, e := range entries {
+		if e.Action == "delete" {
+			assert.NotNil(t, e.Before)
+			assert.Equal(t, "alice", e.Actor)
+			deleted[e.Entity] = append(deleted[e.Entity], e.EntityID)
+		}
+	}
+	assert.Equal(t, map[string][]uuid.UUID{
+		"worker":         {worker.ID},
+		"shift":          {shift.ID},
+		"pattern":        {pattern.ID},
+		"availability":   {availability.ID},
+		"unavailability": {unavailability.ID},
+		"leave":          {leave.ID},
+		"swap":           {swap.ID},
+	}, deleted)
+}
+
+func TestOpenShifts(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	ctx := planner.WithTenant(context.Background(), "a")
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+
+	worker := planner.Worker{ID: uuid.New(), Name: "Ann"}
+	require.NoError(t, db.CreateWorker(ctx, worker))
+	assigned := planner.Shift{ID: uuid.New(), WorkerID: &worker.ID, Date: date, Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0)}
+	open := planner.Shift{ID: uuid.New(), Date: date, Start: planner.NewClock(16, 0), End: planner.NewClock(24, 0)}
+	require.NoError(t, db.CreateShift(ctx, assigned))
+	require.NoError(t, db.CreateShift(ctx, open))
+
+	// open shifts reference no worker
+	var nulls int64
+	require.NoError(t, db.Raw("SELECT COUNT(*) FROM shifts WHERE worker_id IS NULL").Scan(&nulls).Error)
+	assert.Equal(t, int64(1), nulls)
+
+	shifts, err := db.Shifts(ctx, planner.ShiftsFilter{Open: true})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{open.ID}, shiftIDs(shifts))
+
+	// open shifts are sorted first and aren't skipped by cursors
+	shifts, err = db.Shifts(ctx, planner.ShiftsFilter{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{open.ID}, shiftIDs(shifts))
+	after := planner.Cursor{Date: date, ID: open.ID}
+	shifts, err = db.Shifts(ctx, planner.ShiftsFilter{After: &after})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{assigned.ID}, shiftIDs(shifts))
+
+	require.NoError(t, db.ClaimShift(ctx, open.ID, worker.ID))
+	assert.ErrorIs(t, db.ClaimShift(ctx, open.ID, worker.ID), planner.ErrShiftTaken)
+	claimed, err := db.Shift(ctx, open.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &worker.ID, claimed.WorkerID)
+}
+
+// TestConcurrentClaims races claims through planner, so checks of the claimed
+// shift and its audit entry run in the same transaction as the claim.
+func TestConcurrentClaims(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	plan := planner.New(db)
+	ctx := planner.WithTenant(context.Background(), "a")
+
+	const claimants = 20
+	workers := make([]uuid.UUID, claimants)
+	for i := range workers {
+		workers[i] = uuid.New()
+		require.NoError(t, db.CreateWorker(ctx, planner.Worker{ID: workers[i], Name: fmt.Sprint("Worker ", i)}))
+	}
+
+	for round := range 5 {
+		open := planner.Shift{
+			ID: uuid.New(), Date: time.Date(2025, 11, 3+7*round, 0, 0, 0, 0, time.UTC),
+			Start: planner.NewClock(8, 0), End: planner.NewClock(16, 0),
+		}
+		require.NoError(t, db.CreateShift(ctx, open))
+
+		// all claims are released at once
+		start := make(chan struct{})
+		errs := make([]error, claimants)
+		var wg sync.WaitGroup
+		for i, workerID := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, errs[i] = plan.ClaimShift(ctx, open.ID, workerID)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		var winner *uuid.UUID
+		for i, err := range errs {
+			if err == nil {
+				assert.Nil(t, winner, "shift is claimed twice")
+				winner = &workers[i]
+				continue
+			}
+			assert.ErrorIs(t, err, planner.ErrShiftTaken)
+		}
+		require.NotNil(t, winner, "shift isn't claimed")
+		claimed, err := db.Shift(ctx, open.ID)
+		require.NoError(t, err)
+		assert.Equal(t, winner, claimed.WorkerID)
+
+		entries, err := db.AuditEntries(ctx, planner.AuditFilter{Entity: ptr("shift"), EntityID: &open.ID})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "claim", entries[0].Action)
+	}
+}
+
+func TestDailyHours(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+	ctx := planner.WithTenant(context.Background(), "a")
+	date := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	nextDay := date.AddDate(0, 0, 1)
+
+	location := planner.Location{ID: uuid.New(), Name: "North"}
+	require.NoError(t, db.CreateLocation(ctx, location))
+	worker := planner.Worker{ID: uuid.New(), Name: "Ann"}
+	require.NoError(t, db.CreateWorker(ctx, worker))
+	other := planner.Worker{ID: uuid.New(), Name: "Bob"}
+	require.NoError(t, db.CreateWorker(planner.WithTenant(ctx, "b"), other))
+	shift := func(ctx context.Context, workerID *uuid.UUID, date time.Time, start, end planner.Clock, locationID *uuid.UUID) {
+		t.Helper()
+		require.NoError(t, db.CreateShift(ctx, planner.Shift{
+			ID: uuid.New(), WorkerID: workerID, Date: date, Start: start, End: end, LocationID: locationID,
+		}))
+	}
+	shift(ctx, &worker.ID, date, planner.NewClock(6, 0), planner.NewClock(10, 30), nil)
+	// overnight shift counts to the start date
+	shift(ctx, &worker.ID, date, planner.NewClock(22, 0), planner.NewClock(6, 0), &location.ID)
+	shift(ctx, &worker.ID, nextDay, planner.NewClock(16, 0), planner.NewClock(24, 0), &location.ID)
+	shift(ctx, nil, date, planner.NewClock(8, 0), planner.NewClock(16, 0), nil)
+	shift(planner.WithTenant(ctx, "b"), &other.ID, date, planner.NewClock(8, 0), planner.NewClock(16, 0), nil)
+
+	hours, err := db.DailyHours(ctx, planner.ShiftsFilter{From: &date, To: &nextDay})
+	require.NoError(t, err)
+	require.Len(t, hours, 2)
+	assert.Equal(t, planner.DailyHours{WorkerID: worker.ID, Date: date, Shifts: 2, Minutes: 750}, hours[0])
+	assert.Equal(t, planner.DailyHours{WorkerID: worker.ID, Date: nextDay, Shifts: 1, Minutes: 480}, hours[1])
+
+	hours, err = db.DailyHours(ctx, planner.ShiftsFilter{LocationID: &location.ID, To: &date})
+	require.NoError(t, err)
+	assert.Equal(t, []planner.DailyHours{{WorkerID: worker.ID, Date: date, Shifts: 1, Minutes: 480}}, hours)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func shiftIDs(shifts []planner.Shift) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(shifts))
+	for _, s := range shifts {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func workerIDs(workers []planner.Worker) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(workers))
+	for _, w := range workers {
+		ids = append(ids, w.ID)
+	}
+	return ids
+}
@@ -0,0 +1,61 @@
+package sqllite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sp4rd4/wrkpln/planner"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrNoTenant = errors.New("no tenant in context")
+
+// scopeTenant registers callbacks which store tenant of statement context
+// into created and updated records and constrain all queries, updates
+// and deletes to it, so repository methods can't reach other tenants records.
+// Statements without tenant fail with ErrNoTenant.
+func scopeTenant(db *gorm.DB) error {
+	callbacks := db.Callback()
+	err := errors.Join(
+		callbacks.Create().Before("gorm:create").Register("tenant:create", setTenant),
+		callbacks.Query().Before("gorm:query").Register("tenant:query", whereTenant),
+		// Scan and Row of model queries run row callbacks instead of query ones
+		callbacks.Row().Before("gorm:row").Register("tenant:row", whereTenant),
+		callbacks.Update().Before("gorm:update").Register("tenant:update", func(tx *gorm.DB) {
+			setTenant(tx)
+			whereTenant(tx)
+		}),
+		callbacks.Delete().Before("gorm:delete").Register("tenant:delete", whereTenant),
+	)
+	if err != nil {
+		return fmt.Errorf("register tenant callbacks: %w", err)
+	}
+	return nil
+}
+
+func setTenant(tx *gorm.DB) {
+	if tenant, ok := statementTenant(tx); ok {
+		tx.Statement.SetColumn("tenant_id", tenant)
+	}
+}
+
+func whereTenant(tx *gorm.DB) {
+	// raw SQL is used only for schema management
+	if tx.Statement.SQL.Len() > 0 {
+		return
+	}
+	if tenant, ok := statementTenant(tx); ok {
+		tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: tenant},
+		}})
+	}
+}
+
+func statementTenant(tx *gorm.DB) (string, bool) {
+	tenant, ok := planner.TenantFrom(tx.Statement.Context)
+	if !ok {
+		tx.AddError(ErrNoTenant)
+	}
+	return tenant, ok
+}
@@ -2,25 +2,66 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
+	"github.com/sp4rd4/wrkpln/auth"
 	"github.com/sp4rd4/wrkpln/config"
 	handler "github.com/sp4rd4/wrkpln/handler/http"
 	"github.com/sp4rd4/wrkpln/planner"
+	"github.com/sp4rd4/wrkpln/planner/calendar"
+	"github.com/sp4rd4/wrkpln/planner/importer"
+	"github.com/sp4rd4/wrkpln/planner/report"
+	"github.com/sp4rd4/wrkpln/planner/roster"
 	"github.com/sp4rd4/wrkpln/repository/sqllite"
 	"golang.org/x/sync/errgroup"
 )
 
 func Start(ctx context.Context, logger *slog.Logger, cfg config.Config) error {
-	repo, err := sqllite.New(cfg.DBPath, cfg.DBSchemaPath)
+	repo, err := sqllite.New(cfg.DBPath, cfg.DBSchemaPath, cfg.DBMigrationsDir)
 	if err != nil {
 		return fmt.Errorf("repository init: %w", err)
 	}
-	planner := planner.New(repo)
-	h := handler.New(logger, planner)
+	policy, err := conflictPolicy(cfg)
+	if err != nil {
+		return fmt.Errorf("planner init: %w", err)
+	}
+	rules, err := complianceRules(cfg, policy)
+	if err != nil {
+		return fmt.Errorf("planner init: %w", err)
+	}
+	planner := newPlanner(repo, policy, rules)
+	targets, err := report.ParseTargets(cfg.StaffingTargets)
+	if err != nil {
+		return fmt.Errorf("report init: %w", err)
+	}
+	authenticators, err := authenticators(cfg)
+	if err != nil {
+		return fmt.Errorf("auth init: %w", err)
+	}
+	if cfg.AuthDisabled {
+		slog.Warn("authentication is disabled, every caller is an admin")
+	}
+	reporter := report.New(
+		repo,
+		report.Overtime(report.Thresholds{Daily: cfg.OvertimeDaily, Weekly: cfg.OvertimeWeekly}),
+		report.Staffing(targets...),
+	)
+	opts := []handler.Option{handler.DefaultTenant(cfg.DefaultTenant), handler.Authenticators(authenticators...)}
+	if cfg.JWTKey != "" {
+		opts = append(opts, handler.FeedTokens(auth.NewJWT([]byte(cfg.JWTKey)), cfg.FeedTokenTTL))
+	}
+	h := handler.New(
+		logger, planner, roster.New(repo, policy, rules...), reporter, calendar.New(repo), opts...,
+	)
 
 	server := &http.Server{
 		Addr:           ":" + strconv.Itoa(cfg.Port),
@@ -52,3 +93,126 @@ func Start(ctx context.Context, logger *slog.Logger, cfg config.Config) error {
 
 	return eg.Wait()
 }
+
+var ErrImportRejected = errors.New("import rejected")
+
+type ImportOptions struct {
+	Kind importer.Kind
+	// Path is a CSV file or XLSX one if it has .xlsx extension.
+	Path string
+	// Tenant defaults to DefaultTenant of config.
+	Tenant string
+	// Actor is recorded in the audit log as author of the records, it's required.
+	Actor  string
+	DryRun bool
+}
+
+// Import creates workers or shifts of the tenant from file, it writes report
+// of the import to out. ErrImportRejected is returned if any row is invalid.
+func Import(ctx context.Context, cfg config.Config, opts ImportOptions, out io.Writer) error {
+	tenant := opts.Tenant
+	if tenant == "" {
+		tenant = cfg.DefaultTenant
+	}
+	if !planner.ValidTenant(tenant) {
+		return fmt.Errorf("invalid tenant %q", tenant)
+	}
+	if opts.Actor == "" {
+		return errors.New("import actor is required")
+	}
+	ctx = planner.WithActor(planner.WithTenant(ctx, tenant), opts.Actor)
+
+	repo, err := sqllite.New(cfg.DBPath, cfg.DBSchemaPath, cfg.DBMigrationsDir)
+	if err != nil {
+		return fmt.Errorf("repository init: %w", err)
+	}
+	policy, err := conflictPolicy(cfg)
+	if err != nil {
+		return fmt.Errorf("planner init: %w", err)
+	}
+	rules, err := complianceRules(cfg, policy)
+	if err != nil {
+		return fmt.Errorf("planner init: %w", err)
+	}
+	plan := newPlanner(repo, policy, rules)
+	file, err := os.Open(opts.Path)
+	if err != nil {
+		return fmt.Errorf("open import file: %w", err)
+	}
+	defer file.Close()
+
+	format := importer.CSV
+	if strings.EqualFold(filepath.Ext(opts.Path), ".xlsx") {
+		format = importer.XLSX
+	}
+	report, err := importer.New(plan).Import(ctx, opts.Kind, format, file, opts.DryRun)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	if len(report.Errors) > 0 {
+		return ErrImportRejected
+	}
+	return nil
+}
+
+func newPlanner(repo planner.Repository, policy planner.ConflictPolicy, rules []planner.ComplianceRule) planner.Work {
+	return planner.New(repo, planner.ConflictRule(policy), planner.Compliance(rules...))
+}
+
+func complianceRules(cfg config.Config, policy planner.ConflictPolicy) ([]planner.ComplianceRule, error) {
+	rules, err := planner.ParseRules(cfg.ComplianceRules)
+	if err != nil {
+		return nil, err
+	}
+	// both check rest between shifts, so which one applies would be ambiguous
+	if _, ok := policy.(planner.MinRest); ok {
+		for _, rule := range rules {
+			if _, ok := rule.Rule.(planner.MinRestBetween); ok {
+				return nil, errors.New("min_rest compliance rule can't be used with min_rest conflict policy")
+			}
+		}
+	}
+	return rules, nil
+}
+
+func authenticators(cfg config.Config) ([]auth.Authenticator, error) {
+	if cfg.AuthDisabled {
+		if len(cfg.APIKeys) > 0 || cfg.JWTKey != "" {
+			return nil, errors.New("AUTH_DISABLED can't be combined with API_KEYS or JWT_KEY")
+		}
+		return []auth.Authenticator{auth.Anonymous{}}, nil
+	}
+	var authenticators []auth.Authenticator
+	if len(cfg.APIKeys) > 0 {
+		keys, err := auth.ParseAPIKeys(cfg.APIKeys)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, keys)
+	}
+	if cfg.JWTKey != "" {
+		authenticators = append(authenticators, auth.NewJWT([]byte(cfg.JWTKey)))
+	}
+	if len(authenticators) == 0 {
+		return nil, errors.New("no authenticators configured, set API_KEYS or JWT_KEY, or AUTH_DISABLED=true")
+	}
+	return authenticators, nil
+}
+
+func conflictPolicy(cfg config.Config) (planner.ConflictPolicy, error) {
+	switch cfg.ConflictPolicy {
+	case "one_per_day":
+		return planner.OnePerDay{}, nil
+	case "no_overlap":
+		return planner.NoOverlap{}, nil
+	case "min_rest":
+		return planner.MinRest{Rest: cfg.MinRest}, nil
+	default:
+		return nil, fmt.Errorf("unknown conflict policy %q", cfg.ConflictPolicy)
+	}
+}